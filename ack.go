@@ -0,0 +1,230 @@
+package pipelines
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// Acknowledger is notified once payload written to Pipeline with HandleAcked
+// and every event derived from it are handled.
+type Acknowledger interface {
+	// Called when every event derived from payload is handled without errors.
+	Ack()
+	// Called with the first error derived from payload
+	// once every other event derived from it is handled,
+	// or if payload is dropped because Pipeline execution is stopped.
+	Nack(err error)
+}
+
+// AckFuncs is Acknowledger that calls its functions. Nil functions are skipped.
+type AckFuncs struct {
+	OnAck  func()
+	OnNack func(error)
+}
+
+func (a AckFuncs) Ack() {
+	if a.OnAck != nil {
+		a.OnAck()
+	}
+}
+
+func (a AckFuncs) Nack(err error) {
+	if a.OnNack != nil {
+		a.OnNack(err)
+	}
+}
+
+// Handles every payload of seq within single Pipeline execution like HandleSeq
+// and notifies Acknowledger paired with payload once payload is handled.
+// Payload is handled once every event derived from it is either yielded or dropped by a stage.
+// Errors dropped by error handlers count as handled, errors yielded nack the payload.
+// Result that stops iteration nacks its payload with context.Canceled.
+func (pipeline Pipeline[T, U]) HandleAcked(
+	ctx context.Context,
+	seq iter.Seq2[T, Acknowledger],
+	opts ...HandleOptions,
+) iter.Seq2[U, error] {
+	return pipeline.handle(ctx, opts, func(ctx context.Context, w EventWriter[T]) {
+		for payload, acknowledger := range seq {
+			select {
+			case <-ctx.Done():
+				if acknowledger != nil {
+					acknowledger.Nack(ctx.Err())
+				}
+
+				return
+			default:
+			}
+
+			if acknowledger == nil {
+				w.Write(payload)
+
+				continue
+			}
+
+			withAck[T](w, &ack{acknowledger: acknowledger}).Write(payload)
+		}
+	})
+}
+
+// ack counts pending events derived from a single payload
+// and notifies Acknowledger once there are none left.
+type ack struct {
+	acknowledger Acknowledger
+
+	mu       sync.Mutex
+	pending  int
+	err      error
+	resolved bool
+}
+
+// Registers new event derived from payload.
+func (a *ack) derive() {
+	if a == nil {
+		return
+	}
+
+	a.mu.Lock()
+	a.pending++
+	a.mu.Unlock()
+}
+
+// Marks event derived from payload as handled, with err if it failed.
+func (a *ack) done(err error) {
+	if a == nil {
+		return
+	}
+
+	a.mu.Lock()
+	if err != nil && a.err == nil {
+		a.err = err
+	}
+
+	a.pending--
+	if a.pending > 0 || a.resolved {
+		a.mu.Unlock()
+
+		return
+	}
+
+	a.resolved = true
+	err = a.err
+	a.mu.Unlock()
+
+	if err != nil {
+		a.acknowledger.Nack(err)
+
+		return
+	}
+
+	a.acknowledger.Ack()
+}
+
+// Returns ack of event derived from events with acks,
+// which marks each of them as handled once it is handled.
+// Returns nil if none of acks is tracked.
+func joinAcks(acks []*ack) *ack {
+	parents := make([]*ack, 0, len(acks))
+	for _, a := range acks {
+		if a != nil {
+			parents = append(parents, a)
+		}
+	}
+
+	if len(parents) == 0 {
+		return nil
+	}
+
+	return &ack{acknowledger: AckFuncs{
+		OnAck: func() {
+			for _, a := range parents {
+				a.done(nil)
+			}
+		},
+		OnNack: func(err error) {
+			for _, a := range parents {
+				a.done(err)
+			}
+		},
+	}}
+}
+
+// ackedWriter writes events that keep ack of the payload they are derived from.
+type ackedWriter[T any] interface {
+	// Writes event with ack.
+	// Gives up once ctx or Pipeline execution is done, or right away if wait is false and writer is not ready.
+	sendAcked(ctx context.Context, payload T, err error, wait bool, a *ack) error
+}
+
+// Returns EventWriter that registers everything written to it as derived from a.
+func withAck[T any](w EventWriter[T], a *ack) EventWriter[T] {
+	if a == nil {
+		return w
+	}
+
+	aw, ok := w.(ackedWriter[T])
+	if !ok {
+		return w
+	}
+
+	return &ackWriter[T]{EventWriter: w, w: aw, ack: a}
+}
+
+type ackWriter[T any] struct {
+	EventWriter[T]
+
+	w   ackedWriter[T]
+	ack *ack
+}
+
+func (w *ackWriter[T]) Write(e T) {
+	_ = w.send(context.Background(), e, nil, true)
+}
+
+func (w *ackWriter[T]) TryWrite(e T) error {
+	return w.send(context.Background(), e, nil, false)
+}
+
+func (w *ackWriter[T]) WriteContext(ctx context.Context, e T) error {
+	return w.send(ctx, e, nil, true)
+}
+
+func (w *ackWriter[T]) WriteError(err error) {
+	_ = w.send(context.Background(), zero[T](), err, true)
+}
+
+func (w *ackWriter[T]) writeErrorContext(ctx context.Context, err error) error {
+	return w.send(ctx, zero[T](), err, true)
+}
+
+func (w *ackWriter[T]) send(ctx context.Context, payload T, err error, wait bool) error {
+	w.ack.derive()
+
+	if sendErr := w.w.sendAcked(ctx, payload, err, wait, w.ack); sendErr != nil {
+		w.ack.done(sendErr)
+
+		return sendErr
+	}
+
+	return nil
+}
+
+// Writes e to w as it is, keeping its ack.
+func passOn[T any](w EventWriter[T], e *Event[T]) {
+	if aw, ok := w.(ackedWriter[T]); ok {
+		if err := aw.sendAcked(context.Background(), e.Payload, e.Err, true, e.ack); err != nil {
+			e.ack.done(err)
+		}
+
+		return
+	}
+
+	if e.Err != nil {
+		w.WriteError(e.Err)
+	} else {
+		w.Write(e.Payload)
+	}
+
+	e.ack.done(nil)
+}
@@ -0,0 +1,202 @@
+package pipelines_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// ackRecorder records outcome of every payload.
+type ackRecorder struct {
+	mu     sync.Mutex
+	acked  []int
+	nacked map[int]error
+	// number of results yielded when payload was acknowledged
+	yieldedAtAck map[int]int
+	yielded      *int
+	fed          int
+}
+
+func newAckRecorder(yielded *int) *ackRecorder {
+	return &ackRecorder{nacked: make(map[int]error), yieldedAtAck: make(map[int]int), yielded: yielded}
+}
+
+func (r *ackRecorder) seq(n int) func(yield func(int, pipelines.Acknowledger) bool) {
+	return func(yield func(int, pipelines.Acknowledger) bool) {
+		for i := 0; i < n; i++ {
+			acknowledger := pipelines.AckFuncs{
+				OnAck: func() {
+					r.mu.Lock()
+					defer r.mu.Unlock()
+
+					r.acked = append(r.acked, i)
+					r.yieldedAtAck[i] = *r.yielded
+				},
+				OnNack: func(err error) {
+					r.mu.Lock()
+					defer r.mu.Unlock()
+
+					r.nacked[i] = err
+				},
+			}
+
+			r.mu.Lock()
+			r.fed++
+			r.mu.Unlock()
+
+			if !yield(i, acknowledger) {
+				return
+			}
+		}
+	}
+}
+
+func (r *ackRecorder) unresolved() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.fed - len(r.acked) - len(r.nacked)
+}
+
+func (r *ackRecorder) resolved() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.acked) + len(r.nacked)
+}
+
+var _ = Describe("Ack", func() {
+	ctx := context.TODO()
+
+	fanOut := func(ctx context.Context, w pipelines.EventWriter[int], e int) {
+		for i := 0; i < 3; i++ {
+			w.Write(e)
+		}
+	}
+
+	It("should ack payload once every derived event is handled", func() {
+		yielded := 0
+		recorder := newAckRecorder(&yielded)
+
+		c := pipelines.Pipe(pipelines.PassThrough[int]().Pipeline(), fanOut, pipelines.WithHandlerPool(4))
+		for _, err := range c.HandleAcked(ctx, recorder.seq(1)) {
+			Expect(err).ShouldNot(HaveOccurred())
+
+			recorder.mu.Lock()
+			Expect(recorder.acked).To(BeEmpty())
+			yielded++
+			recorder.mu.Unlock()
+		}
+
+		Expect(recorder.acked).To(Equal([]int{0}))
+		Expect(recorder.yieldedAtAck[0]).To(Equal(3))
+	})
+
+	It("should ack payload with every derived event dropped", func() {
+		yielded := 0
+		recorder := newAckRecorder(&yielded)
+
+		c := pipelines.Pipe2(
+			pipelines.PassThrough[int]().Pipeline(),
+			fanOut,
+			pipelines.Filter(func(e int) bool { return e != 0 }),
+		)
+
+		for range c.HandleAcked(ctx, recorder.seq(2)) {
+		}
+
+		Expect(recorder.acked).To(ConsistOf(0, 1))
+	})
+
+	It("should nack payload with derived error", func() {
+		yielded := 0
+		recorder := newAckRecorder(&yielded)
+
+		failTwo := func(ctx context.Context, e int) (int, error) {
+			if e == 2 {
+				return 0, fmt.Errorf("two")
+			}
+
+			return e, nil
+		}
+
+		c := pipelines.Pipe2(
+			pipelines.PassThrough[int]().Pipeline(),
+			fanOut,
+			pipelines.HandleFunc(failTwo),
+			pipelines.WithHandlerPool(4),
+			pipelines.WithOrderPreserved(),
+		)
+
+		for range c.HandleAcked(ctx, recorder.seq(4)) {
+		}
+
+		Expect(recorder.acked).To(ConsistOf(0, 1, 3))
+		Expect(recorder.nacked).To(HaveLen(1))
+		Expect(recorder.nacked[2]).To(MatchError("error processing int: two"))
+	})
+
+	It("should ack payload whose errors are handled by stage", func() {
+		yielded := 0
+		recorder := newAckRecorder(&yielded)
+		dl := pipelines.NewMemoryDeadLetter[int]()
+
+		c := pipelines.PipeDeadLetter(
+			pipelines.Pipe(pipelines.PassThrough[int]().Pipeline(), pipelines.HandleFunc(func(ctx context.Context, e int) (int, error) {
+				return 0, fmt.Errorf("failed")
+			})),
+			dl,
+		)
+
+		for range c.HandleAcked(ctx, recorder.seq(3)) {
+		}
+
+		Expect(recorder.acked).To(ConsistOf(0, 1, 2))
+		Expect(dl.Letters()).To(HaveLen(3))
+	})
+
+	It("should ack batched payloads once batch is handled", func() {
+		yielded := 0
+		recorder := newAckRecorder(&yielded)
+
+		c := pipelines.Batch(pipelines.PassThrough[int]().Pipeline(), 2, time.Second)
+
+		for batch, err := range c.HandleAcked(ctx, recorder.seq(5)) {
+			Expect(err).ShouldNot(HaveOccurred())
+
+			recorder.mu.Lock()
+			yielded += len(batch)
+			recorder.mu.Unlock()
+		}
+
+		Expect(recorder.acked).To(HaveLen(5))
+		Expect(recorder.yieldedAtAck).To(Equal(map[int]int{0: 2, 1: 2, 2: 4, 3: 4, 4: 5}))
+	})
+
+	It("should nack payloads dropped when execution is stopped", func() {
+		yielded := 0
+		recorder := newAckRecorder(&yielded)
+
+		c := pipelines.Pipe(pipelines.PassThrough[int]().Pipeline(), fanOut, pipelines.WithHandlerPool(4))
+
+		for range c.HandleAcked(ctx, recorder.seq(20)) {
+			break
+		}
+
+		Eventually(recorder.unresolved).Should(BeZero())
+		Expect(recorder.resolved()).To(BeNumerically(">", 0))
+
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+
+		Expect(recorder.acked).To(BeEmpty())
+		for _, err := range recorder.nacked {
+			Expect(err).To(MatchError(context.Canceled))
+		}
+	})
+})
@@ -0,0 +1,86 @@
+package pipelines
+
+import (
+	"context"
+	"time"
+)
+
+// Returns `Pipeline[T, []U]` that groups events of p into batches.
+// Batch is passed on once it has size events, maxWait elapsed since its first event
+// or p has no more events.
+// Size below 1 or non-positive maxWait disable corresponding limit.
+// Errors are passed on as they arrive.
+func Batch[T, U any](p Pipeline[T, U], size int, maxWait time.Duration) Pipeline[T, []U] {
+	return func(ctx context.Context) (EventWriterCloser[T], EventReader[[]U], int) {
+		w, r, pool := p(ctx)
+		rw := newEventRW[[]U](ctx, 0)
+		out := rw.GetWriter()
+
+		go func() {
+			var (
+				batch   []U
+				acks    []*ack
+				timer   *time.Timer
+				timeout <-chan time.Time
+			)
+
+			flush := func() {
+				if timer != nil {
+					timer.Stop()
+					timer, timeout = nil, nil
+				}
+
+				if len(batch) > 0 {
+					withAck(out, joinAcks(acks)).Write(batch)
+					batch, acks = nil, nil
+				}
+			}
+
+			defer out.Close()
+
+			for {
+				select {
+				case <-timeout:
+					flush()
+				case event, ok := <-r.Read():
+					if !ok {
+						flush()
+
+						return
+					}
+
+					if event.Err != nil {
+						withAck(out, event.ack).WriteError(event.Err)
+						event.ack.done(nil)
+
+						continue
+					}
+
+					batch = append(batch, event.Payload)
+					acks = append(acks, event.ack)
+					r.Dispose(event)
+
+					if len(batch) == 1 && maxWait > 0 {
+						timer = time.NewTimer(maxWait)
+						timeout = timer.C
+					}
+
+					if size > 0 && len(batch) >= size {
+						flush()
+					}
+				}
+			}
+		}()
+
+		return w, rw, pool
+	}
+}
+
+// Handler that writes every element of received batch as separate event.
+func Flatten[T any]() Handler[[]T, T] {
+	return func(ctx context.Context, w EventWriter[T], payload []T) {
+		for _, v := range payload {
+			w.Write(v)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package pipelines_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Batch", func() {
+	ctx := context.TODO()
+
+	payloads := func(n int) func(yield func(int) bool) {
+		return func(yield func(int) bool) {
+			for i := 0; i < n; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+	}
+
+	It("should group events by size and flush remainder", func() {
+		c := pipelines.Batch(pipelines.PassThrough[int]().Pipeline(), 3, 0)
+
+		accumulated := [][]int{}
+		for batch, err := range c.HandleSeq(ctx, payloads(7)) {
+			Expect(err).ShouldNot(HaveOccurred())
+			accumulated = append(accumulated, batch)
+		}
+
+		Expect(accumulated).To(Equal([][]int{{0, 1, 2}, {3, 4, 5}, {6}}))
+	})
+
+	It("should flush batch after maxWait", func() {
+		payloads := make(chan int)
+		go func() {
+			payloads <- 1
+			payloads <- 2
+			time.Sleep(time.Millisecond * 100)
+			payloads <- 3
+			close(payloads)
+		}()
+
+		c := pipelines.Batch(pipelines.PassThrough[int]().Pipeline(), 10, time.Millisecond*20)
+
+		accumulated := [][]int{}
+		for batch, err := range c.HandleChan(ctx, payloads) {
+			Expect(err).ShouldNot(HaveOccurred())
+			accumulated = append(accumulated, batch)
+		}
+
+		Expect(accumulated).To(Equal([][]int{{1, 2}, {3}}))
+	})
+
+	It("should pass errors on", func() {
+		failOdd := pipelines.HandleFunc(pipelines.LiftErr(func(_ context.Context, n int) error {
+			if n%2 == 1 {
+				return errors.New("odd")
+			}
+
+			return nil
+		}))
+		c := pipelines.Batch(failOdd.Pipeline(), 0, 0)
+
+		accumulated := [][]int{}
+		errs := 0
+		for batch, err := range c.HandleSeq(ctx, payloads(6)) {
+			if err != nil {
+				errs++
+
+				continue
+			}
+
+			accumulated = append(accumulated, batch)
+		}
+
+		Expect(errs).To(Equal(3))
+		Expect(accumulated).To(Equal([][]int{{0, 2, 4}}))
+	})
+
+	It("should flatten batches", func() {
+		c := pipelines.Pipe(pipelines.Batch(pipelines.PassThrough[int]().Pipeline(), 2, 0), pipelines.Flatten[int]())
+
+		accumulated := []int{}
+		for value, err := range c.HandleSeq(ctx, payloads(5)) {
+			Expect(err).ShouldNot(HaveOccurred())
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(Equal([]int{0, 1, 2, 3, 4}))
+	})
+})
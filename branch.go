@@ -0,0 +1,155 @@
+package pipelines
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrNoRoute = errors.New("no branch to route event to")
+
+// Returns `Pipeline[T, N]` that sends every event of p to each of branches and merges their results.
+// Errors of p are passed on once, without reaching branches.
+func Broadcast[T, U, N any](p Pipeline[T, U], branches ...Pipeline[U, N]) Pipeline[T, N] {
+	return branch(p, func(_ U, writers []EventWriterCloser[U]) ([]EventWriterCloser[U], bool) {
+		return writers, true
+	}, branches)
+}
+
+// Returns `Pipeline[T, N]` that sends every event of p to the branch with index returned by selector
+// and merges results of all branches.
+// Events selector returns invalid index for are passed on as Error with ErrNoRoute.
+func Route[T, U, N any](p Pipeline[T, U], selector func(U) int, branches ...Pipeline[U, N]) Pipeline[T, N] {
+	return branch(p, func(payload U, writers []EventWriterCloser[U]) ([]EventWriterCloser[U], bool) {
+		i := selector(payload)
+		if i < 0 || i >= len(writers) {
+			return nil, false
+		}
+
+		return writers[i : i+1], true
+	}, branches)
+}
+
+func branch[T, U, N any](
+	p Pipeline[T, U],
+	selectWriters func(U, []EventWriterCloser[U]) ([]EventWriterCloser[U], bool),
+	branches []Pipeline[U, N],
+) Pipeline[T, N] {
+	return func(ctx context.Context) (EventWriterCloser[T], EventReader[N], int) {
+		w, r, pool := p(ctx)
+		rw := newEventRW[N](ctx, 0)
+
+		errWriter := rw.GetWriter()
+		writers := make([]EventWriterCloser[U], len(branches))
+		for i, b := range branches {
+			bw, br, branchPool := b(ctx)
+			if pool < branchPool {
+				pool = branchPool
+			}
+
+			writers[i] = bw
+			go forward(br, rw.GetWriter())
+		}
+
+		go func() {
+			for event := range r.Read() {
+				a := event.ack
+				if event.Err != nil {
+					withAck[N](errWriter, a).WriteError(event.Err)
+					a.done(nil)
+
+					continue
+				}
+
+				selected, ok := selectWriters(event.Payload, writers)
+				if !ok {
+					withAck[N](errWriter, a).WriteError(NewError(ErrNoRoute, event.Payload))
+				}
+
+				for _, bw := range selected {
+					withAck[U](bw, a).Write(event.Payload)
+				}
+
+				r.Dispose(event)
+				a.done(nil)
+			}
+
+			for _, bw := range writers {
+				bw.Close()
+			}
+
+			errWriter.Close()
+		}()
+
+		return w, rw, pool
+	}
+}
+
+// Writes every event of r to w and closes w once r is exhausted.
+func forward[T any](r EventReader[T], w EventWriterCloser[T]) {
+	for event := range r.Read() {
+		passOn(w, event)
+		r.Dispose(event)
+	}
+
+	w.Close()
+}
+
+// Joined holds results every Join branch produced for a single payload.
+type Joined[U, N any] struct {
+	Payload U
+	// Results of each branch in the order branches were passed to Join.
+	Results [][]N
+}
+
+// Returns `Pipeline[T, R]` that handles every event of p with each of branches
+// and combines their results keyed by that event.
+// Every event is handled by a separate execution of each branch.
+// If any branch produces errors, they are passed on and combine is not called.
+func Join[T, U, N, R any](p Pipeline[T, U], combine Handle[Joined[U, N], R], branches ...Pipeline[U, N]) Pipeline[T, R] {
+	return Pipe(p, func(ctx context.Context, w EventWriter[R], payload U) {
+		joined := Joined[U, N]{Payload: payload, Results: make([][]N, len(branches))}
+		errs := make([][]error, len(branches))
+
+		var wg sync.WaitGroup
+		for i, b := range branches {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for v, err := range b.Handle(ctx, payload) {
+					if err != nil {
+						errs[i] = append(errs[i], err)
+
+						continue
+					}
+
+					joined.Results[i] = append(joined.Results[i], v)
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		failed := false
+		for _, branchErrs := range errs {
+			for _, err := range branchErrs {
+				failed = true
+				w.WriteError(err)
+			}
+		}
+
+		if failed {
+			return
+		}
+
+		v, err := combine(ctx, joined)
+		if err != nil {
+			w.WriteError(NewError(err, payload))
+
+			return
+		}
+
+		w.Write(v)
+	})
+}
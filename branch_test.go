@@ -0,0 +1,138 @@
+package pipelines_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Branch", func() {
+	ctx := context.TODO()
+
+	lift := func(fn func(int) int) pipelines.Handler[int, int] {
+		return pipelines.HandleFunc(pipelines.LiftOk(func(_ context.Context, n int) int { return fn(n) }))
+	}
+	times10 := lift(func(n int) int { return n * 10 }).Pipeline()
+	plus1 := lift(func(n int) int { return n + 1 }).Pipeline()
+
+	payloads := func(yield func(int) bool) {
+		for i := 1; i <= 3; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	It("should broadcast events to every branch", func() {
+		c := pipelines.Pipe(
+			pipelines.Broadcast(pipelines.PassThrough[int]().Pipeline(), times10, plus1),
+			pipelines.HandleFunc(pipelines.LiftOk(func(_ context.Context, n int) string { return fmt.Sprint(n) })),
+		)
+
+		accumulated := []string{}
+		for value, err := range c.HandleSeq(ctx, payloads) {
+			Expect(err).ShouldNot(HaveOccurred())
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(ConsistOf("10", "20", "30", "2", "3", "4"))
+	})
+
+	It("should pass on errors once", func() {
+		failing := pipelines.HandleFunc(
+			pipelines.LiftErr(func(context.Context, int) error { return errors.New("failed") }),
+		).Pipeline()
+		c := pipelines.Broadcast(failing, times10, plus1)
+
+		errs := 0
+		for _, err := range c.Handle(ctx, 1) {
+			Expect(err).Should(MatchError("error processing int: failed"))
+			errs++
+		}
+
+		Expect(errs).To(Equal(1))
+	})
+
+	It("should route events by selector", func() {
+		c := pipelines.Route(
+			pipelines.PassThrough[int]().Pipeline(),
+			func(n int) int { return n % 2 },
+			times10, plus1,
+		)
+
+		accumulated := []int{}
+		for value, err := range c.HandleSeq(ctx, payloads) {
+			Expect(err).ShouldNot(HaveOccurred())
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(ConsistOf(2, 20, 4))
+	})
+
+	It("should report events without route", func() {
+		c := pipelines.Route(
+			pipelines.PassThrough[int]().Pipeline(),
+			func(n int) int { return n },
+			times10, plus1,
+		)
+
+		accumulated := []int{}
+		errs := []error{}
+		for value, err := range c.HandleSeq(ctx, payloads) {
+			if err != nil {
+				errs = append(errs, err)
+
+				continue
+			}
+
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(ConsistOf(2))
+		Expect(errs).To(HaveLen(2))
+		for _, err := range errs {
+			Expect(err).Should(MatchError(pipelines.ErrNoRoute))
+		}
+	})
+
+	It("should join branch results by payload", func() {
+		combine := func(_ context.Context, joined pipelines.Joined[int, int]) (string, error) {
+			return fmt.Sprintf("%d: %v", joined.Payload, joined.Results), nil
+		}
+
+		c := pipelines.Join(pipelines.PassThrough[int]().Pipeline(), combine, times10, plus1)
+
+		accumulated := []string{}
+		for value, err := range c.HandleSeq(ctx, payloads) {
+			Expect(err).ShouldNot(HaveOccurred())
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(ConsistOf("1: [[10] [2]]", "2: [[20] [3]]", "3: [[30] [4]]"))
+	})
+
+	It("should not combine results if branch failed", func() {
+		failing := pipelines.HandleFunc(
+			pipelines.LiftErr(func(context.Context, int) error { return errors.New("failed") }),
+		).Pipeline()
+		combine := func(_ context.Context, joined pipelines.Joined[int, int]) (int, error) {
+			Fail("should not be called")
+
+			return 0, nil
+		}
+
+		c := pipelines.Join(pipelines.PassThrough[int]().Pipeline(), combine, times10, failing)
+
+		errs := 0
+		for _, err := range c.Handle(ctx, 1) {
+			Expect(err).Should(MatchError("error processing int: failed"))
+			errs++
+		}
+
+		Expect(errs).To(Equal(1))
+	})
+})
@@ -0,0 +1,121 @@
+package pipelines
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var ErrStageMismatch = errors.New("stage does not match pipeline")
+
+// Stage is a Handler with its options, that can be added to a Builder.
+// Stage hides Handler types, so stages can be chosen at runtime.
+type Stage struct {
+	in, out reflect.Type
+	// receives EventReader[in] and returns EventReader[out]
+	start func(ctx context.Context, r any, pool int) (any, int)
+}
+
+// Returns Stage that handles events with h configured by opts.
+func NewStage[T, U any](h Handler[T, U], opts ...HandlerOptions) Stage {
+	o := newStageOptions[T](opts)
+
+	return Stage{
+		in:  reflect.TypeFor[T](),
+		out: reflect.TypeFor[U](),
+		start: func(ctx context.Context, r any, pool int) (any, int) {
+			o := o
+			if o.pool < pool {
+				o.pool = pool
+			}
+
+			return startWorkers(ctx, h, r.(EventReader[T]), o), o.pool
+		},
+	}
+}
+
+// Returns Stage input type.
+func (s Stage) In() reflect.Type {
+	return s.in
+}
+
+// Returns Stage output type.
+func (s Stage) Out() reflect.Type {
+	return s.out
+}
+
+// Builder assembles `Pipeline[T, U]` from any number of stages.
+// Stage types are validated as stages are added and once more on Build.
+type Builder[T, U any] struct {
+	stages []Stage
+	out    reflect.Type
+	err    error
+}
+
+// Returns Builder of `Pipeline[T, U]`.
+func NewBuilder[T, U any]() *Builder[T, U] {
+	return &Builder[T, U]{out: reflect.TypeFor[T]()}
+}
+
+// Adds stages to the end of Pipeline.
+// Once a stage does not accept output of the previous one, Builder keeps the error and ignores next stages.
+func (b *Builder[T, U]) Then(stages ...Stage) *Builder[T, U] {
+	for _, s := range stages {
+		if b.err != nil {
+			return b
+		}
+
+		if s.start == nil {
+			b.err = fmt.Errorf("%w: stage %d is not initialized", ErrStageMismatch, len(b.stages))
+
+			return b
+		}
+
+		if s.in != b.out {
+			b.err = fmt.Errorf("%w: stage %d expects %s, got %s", ErrStageMismatch, len(b.stages), s.in, b.out)
+
+			return b
+		}
+
+		b.stages = append(b.stages, s)
+		b.out = s.out
+	}
+
+	return b
+}
+
+// Returns error that occurred while adding stages.
+func (b *Builder[T, U]) Err() error {
+	return b.err
+}
+
+// Returns `Pipeline[T, U]` made of added stages.
+// Returns error if stages could not be chained or the last one does not produce U.
+func (b *Builder[T, U]) Build() (Pipeline[T, U], error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	if out := reflect.TypeFor[U](); b.out != out {
+		return nil, fmt.Errorf("%w: pipeline produces %s, expected %s", ErrStageMismatch, b.out, out)
+	}
+
+	stages := append([]Stage(nil), b.stages...)
+
+	return func(ctx context.Context) (EventWriterCloser[T], EventReader[U], int) {
+		rw := newEventRW[T](ctx, 0)
+		w := rw.GetWriter()
+
+		var (
+			r    any = EventReader[T](rw)
+			pool int
+		)
+
+		for _, s := range stages {
+			r, pool = s.start(ctx, r, pool)
+		}
+
+		return w, r.(EventReader[U]), pool
+	}, nil
+}
@@ -0,0 +1,98 @@
+package pipelines_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Builder", func() {
+	ctx := context.TODO()
+
+	inc := pipelines.NewStage(
+		pipelines.HandleFunc(pipelines.LiftOk(func(_ context.Context, n int) int { return n + 1 })),
+	)
+	double := pipelines.NewStage(
+		pipelines.HandleFunc(pipelines.LiftOk(func(_ context.Context, n int) int { return n * 2 })),
+		pipelines.WithHandlerPool(2),
+	)
+	format := pipelines.NewStage(
+		pipelines.HandleFunc(pipelines.LiftOk(func(_ context.Context, n int) string { return fmt.Sprint(n) })),
+	)
+	parse := pipelines.NewStage(pipelines.HandleFunc(pipelines.LiftNoContext(strconv.Atoi)))
+
+	It("should build pipeline of any length", func() {
+		stages := []pipelines.Stage{}
+		for i := 0; i < 10; i++ {
+			stages = append(stages, inc)
+		}
+
+		c, err := pipelines.NewBuilder[int, string]().
+			Then(stages...).
+			Then(double, format, parse, inc, format).
+			Build()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		accumulated := []string{}
+		for value, err := range c.Handle(ctx, 0) {
+			Expect(err).ShouldNot(HaveOccurred())
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(Equal([]string{"21"}))
+	})
+
+	It("should build pipeline from stages chosen at runtime", func() {
+		registry := map[string]pipelines.Stage{"inc": inc, "double": double}
+		config := []string{"double", "inc", "double"}
+
+		b := pipelines.NewBuilder[int, int]()
+		for _, name := range config {
+			b.Then(registry[name])
+		}
+
+		c, err := b.Build()
+		Expect(err).ShouldNot(HaveOccurred())
+
+		for value, err := range c.Handle(ctx, 1) {
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).To(Equal(6))
+		}
+	})
+
+	It("should compose with Pipe", func() {
+		p, err := pipelines.NewBuilder[int, int]().Then(inc, double).Build()
+		Expect(err).ShouldNot(HaveOccurred())
+
+		c := pipelines.Pipe(p, pipelines.HandleFunc(pipelines.LiftOk(func(_ context.Context, n int) string { return fmt.Sprint(n) })))
+		for value, err := range c.Handle(ctx, 1) {
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).To(Equal("4"))
+		}
+	})
+
+	It("should report mismatched stages", func() {
+		b := pipelines.NewBuilder[int, int]().Then(inc, parse, inc)
+
+		Expect(b.Err()).Should(MatchError(pipelines.ErrStageMismatch))
+		Expect(b.Err()).Should(MatchError("stage does not match pipeline: stage 1 expects string, got int"))
+
+		_, err := b.Build()
+		Expect(err).Should(MatchError(pipelines.ErrStageMismatch))
+	})
+
+	It("should report mismatched output", func() {
+		_, err := pipelines.NewBuilder[int, int]().Then(inc, format).Build()
+
+		Expect(err).Should(MatchError("stage does not match pipeline: pipeline produces string, expected int"))
+
+		_, err = pipelines.NewBuilder[int, int]().Then(pipelines.Stage{}).Build()
+
+		Expect(err).Should(MatchError(pipelines.ErrStageMismatch))
+	})
+})
@@ -0,0 +1,414 @@
+package pipelines
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Progress of Pipeline execution committed by HandleCheckpointed.
+type Checkpoint struct {
+	// Number of payloads handled completely.
+	Offset int `json:"offset"`
+	// Snapshots of states by name.
+	States map[string][]byte `json:"states,omitempty"`
+	// Time checkpoint was committed.
+	Time time.Time `json:"time"`
+}
+
+// Checkpointer stores Checkpoint by Pipeline execution ID.
+type Checkpointer interface {
+	// Returns Checkpoint stored for id and whether it exists.
+	Load(ctx context.Context, id string) (Checkpoint, bool, error)
+	// Stores Checkpoint for id.
+	Save(ctx context.Context, id string, checkpoint Checkpoint) error
+	// Removes Checkpoint stored for id.
+	Delete(ctx context.Context, id string) error
+}
+
+// CheckpointOptions receives current checkpoint options and returns updated ones.
+type CheckpointOptions func(checkpointOptions) checkpointOptions
+
+type checkpointOptions struct {
+	interval int
+	states   map[string]Snapshotter
+}
+
+// Option that specifies number of payloads handled between checkpoints. Defaults to 100.
+func WithCheckpointInterval(payloads int) CheckpointOptions {
+	return func(o checkpointOptions) checkpointOptions {
+		if payloads > 0 {
+			o.interval = payloads
+		}
+
+		return o
+	}
+}
+
+// Option that saves snapshot of state along with every checkpoint under name
+// and restores it on resume.
+func WithCheckpointState(name string, state Snapshotter) CheckpointOptions {
+	return func(o checkpointOptions) checkpointOptions {
+		states := make(map[string]Snapshotter, len(o.states)+1)
+		for k, v := range o.states {
+			states[k] = v
+		}
+
+		states[name] = state
+		o.states = states
+
+		return o
+	}
+}
+
+// Handles payloads of seq within single Pipeline execution like HandleSeq and commits progress to checkpointer under id.
+// If checkpoint for id exists, payloads it covers are skipped and states are restored from it,
+// so execution resumes from the last checkpoint.
+//
+// Payload is handled once every event derived from it is handled, see HandleAcked.
+// Checkpoint is committed once every payload up to its offset is handled
+// and offset advanced by checkpoint interval since previous checkpoint, and once seq is exhausted.
+// Payloads after the last checkpoint are handled again on resume, so delivery is at-least-once.
+// Checkpoint is kept after seq is exhausted, delete it to handle seq from the start with the same id.
+//
+// With states, input is paused at every checkpoint until every payload written so far is handled,
+// so snapshots match the offset. Stages that keep events until next payloads arrive,
+// like Batch without maxWait or event time windows, stall execution at checkpoint then.
+func (pipeline Pipeline[T, U]) HandleCheckpointed(
+	ctx context.Context,
+	id string,
+	checkpointer Checkpointer,
+	seq iter.Seq[T],
+	opts ...CheckpointOptions,
+) iter.Seq2[U, error] {
+	o := checkpointOptions{interval: 100}
+	for _, option := range opts {
+		o = option(o)
+	}
+
+	return func(yield func(U, error) bool) {
+		checkpoint, ok, err := checkpointer.Load(ctx, id)
+		if err != nil {
+			yield(zero[U](), fmt.Errorf("failed to load checkpoint %q: %w", id, err))
+
+			return
+		}
+
+		if ok {
+			for name, state := range o.states {
+				snapshot, ok := checkpoint.States[name]
+				if !ok {
+					continue
+				}
+
+				if err := state.Restore(ctx, bytes.NewReader(snapshot)); err != nil {
+					yield(zero[U](), fmt.Errorf("failed to restore state %q: %w", name, err))
+
+					return
+				}
+			}
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		p := &progress{
+			committed: checkpoint.Offset,
+			handled:   checkpoint.Offset,
+			fed:       checkpoint.Offset,
+			done:      make(map[int]bool),
+			changed:   make(chan struct{}),
+			interval:  o.interval,
+			// without states handled offset can be committed right away
+			eager: len(o.states) == 0,
+			save: func(offset int) error {
+				return o.save(ctx, id, checkpointer, Checkpoint{Offset: offset, Time: time.Now()})
+			},
+			ctx:    ctx,
+			cancel: cancel,
+		}
+
+		payloads := func(yield func(T, Acknowledger) bool) {
+			skip := checkpoint.Offset
+			for payload := range seq {
+				if skip > 0 {
+					skip--
+
+					continue
+				}
+
+				if !p.eager && !p.await(ctx) {
+					return
+				}
+
+				if !yield(payload, p.feed()) {
+					return
+				}
+			}
+		}
+
+		for v, err := range pipeline.HandleAcked(ctx, payloads) {
+			if !yield(v, err) {
+				p.halt()
+
+				return
+			}
+		}
+
+		if ctx.Err() == nil {
+			p.commitAll()
+		}
+
+		if err := p.error(); err != nil {
+			yield(zero[U](), err)
+		}
+	}
+}
+
+// progress tracks offset of payloads handled in order and commits checkpoints of it.
+type progress struct {
+	mu sync.Mutex
+
+	// offset of the last checkpoint
+	committed int
+	// offset every payload before which is handled
+	handled int
+	// number of payloads written to Pipeline
+	fed int
+	// payloads after handled offset that are handled already
+	done map[int]bool
+	// payload was dropped without being handled, so offset can not advance anymore
+	stuck bool
+	// consumer stopped iteration
+	halted bool
+	// closed once handled offset changes
+	changed chan struct{}
+
+	interval int
+	eager    bool
+	save     func(offset int) error
+	ctx      context.Context
+	cancel   context.CancelFunc
+	err      error
+}
+
+// Registers next payload and returns Acknowledger of it.
+func (p *progress) feed() Acknowledger {
+	p.mu.Lock()
+	offset := p.fed
+	p.fed++
+	p.mu.Unlock()
+
+	return AckFuncs{
+		OnAck:  func() { p.handle(offset, nil) },
+		OnNack: func(err error) { p.handle(offset, err) },
+	}
+}
+
+func (p *progress) handle(offset int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// failed payloads and ones discarded by stages are handled, ones dropped once execution is stopped are not
+	if err != nil && (p.halted || p.ctx.Err() != nil) {
+		p.stuck = true
+	} else {
+		p.done[offset] = true
+		for p.done[p.handled] {
+			delete(p.done, p.handled)
+			p.handled++
+		}
+	}
+
+	close(p.changed)
+	p.changed = make(chan struct{})
+
+	if p.eager && p.handled-p.committed >= p.interval {
+		p.commit()
+	}
+}
+
+// Waits until every payload written is handled and commits it, if offset is due for checkpoint.
+// Returns false if next payloads should not be written anymore.
+func (p *progress) await(ctx context.Context) bool {
+	for {
+		p.mu.Lock()
+		if p.stuck || p.err != nil {
+			p.mu.Unlock()
+
+			return false
+		}
+
+		if p.fed-p.committed < p.interval {
+			p.mu.Unlock()
+
+			return true
+		}
+
+		if p.handled == p.fed {
+			p.commit()
+			ok := p.err == nil
+			p.mu.Unlock()
+
+			return ok
+		}
+
+		changed := p.changed
+		p.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-changed:
+		}
+	}
+}
+
+// Marks execution as stopped by consumer.
+func (p *progress) halt() {
+	p.mu.Lock()
+	p.halted = true
+	p.mu.Unlock()
+}
+
+// Commits handled offset once Pipeline execution is finished.
+func (p *progress) commitAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.eager || p.handled == p.fed {
+		p.commit()
+	}
+}
+
+func (p *progress) commit() {
+	if p.err != nil || p.handled == p.committed {
+		return
+	}
+
+	if err := p.save(p.handled); err != nil {
+		p.err = err
+		p.cancel()
+
+		return
+	}
+
+	p.committed = p.handled
+}
+
+func (p *progress) error() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.err
+}
+
+func (o checkpointOptions) save(ctx context.Context, id string, checkpointer Checkpointer, checkpoint Checkpoint) error {
+	if len(o.states) > 0 {
+		checkpoint.States = make(map[string][]byte, len(o.states))
+	}
+
+	for name, state := range o.states {
+		var snapshot bytes.Buffer
+		if err := state.Snapshot(ctx, &snapshot); err != nil {
+			return fmt.Errorf("failed to snapshot state %q: %w", name, err)
+		}
+
+		checkpoint.States[name] = snapshot.Bytes()
+	}
+
+	if err := checkpointer.Save(ctx, id, checkpoint); err != nil {
+		return fmt.Errorf("failed to save checkpoint %q: %w", id, err)
+	}
+
+	return nil
+}
+
+// Returns Checkpointer that keeps every Checkpoint as JSON file in dir.
+// Directory is created if it does not exist.
+func NewFileCheckpointer(dir string) *FileCheckpointer {
+	return &FileCheckpointer{dir: dir}
+}
+
+// Checkpointer that keeps every Checkpoint as JSON file.
+type FileCheckpointer struct {
+	dir string
+}
+
+func (c *FileCheckpointer) Load(_ context.Context, id string) (Checkpoint, bool, error) {
+	var checkpoint Checkpoint
+
+	data, err := os.ReadFile(c.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoint, false, nil
+	}
+
+	if err != nil {
+		return checkpoint, false, err
+	}
+
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return checkpoint, false, err
+	}
+
+	return checkpoint, true, nil
+}
+
+// Replaces file of id atomically, so crash while saving leaves previous Checkpoint intact.
+func (c *FileCheckpointer) Save(_ context.Context, id string, checkpoint Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+
+	path := c.path(id)
+
+	tmp, err := os.CreateTemp(c.dir, filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (c *FileCheckpointer) Delete(_ context.Context, id string) error {
+	if err := os.Remove(c.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (c *FileCheckpointer) path(id string) string {
+	return filepath.Join(c.dir, url.PathEscape(id)+".json")
+}
@@ -0,0 +1,131 @@
+package pipelines_test
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checkpoint", func() {
+	ctx := context.TODO()
+
+	payloads := func(n int) func(yield func(int) bool) {
+		return func(yield func(int) bool) {
+			for i := 0; i < n; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+	}
+
+	sum := pipelines.StatefulHandler[int, int, int](
+		func(ctx context.Context, w pipelines.EventWriter[int], e int, state *pipelines.State[int]) {
+			total, _, _ := state.Load()
+			_ = state.Store(total + e)
+
+			w.Write(e)
+		},
+	)
+
+	It("should resume from the last checkpoint", func() {
+		checkpointer := pipelines.NewFileCheckpointer(filepath.Join(GinkgoT().TempDir(), "checkpoints"))
+
+		run := func(stopAfter int) ([]int, *pipelines.MemoryStateStore[int]) {
+			store := pipelines.NewMemoryStateStore[int]()
+			c := pipelines.Pipe(
+				pipelines.PassThrough[int]().Pipeline(),
+				sum.Handler(store, func(int) string { return "total" }),
+			)
+
+			handled := []int{}
+			for value, err := range c.HandleCheckpointed(
+				ctx, "sum", checkpointer, payloads(25),
+				pipelines.WithCheckpointInterval(10),
+				pipelines.WithCheckpointState("sum", store),
+			) {
+				Expect(err).ShouldNot(HaveOccurred())
+				handled = append(handled, value)
+
+				if len(handled) == stopAfter {
+					break
+				}
+			}
+
+			return handled, store
+		}
+
+		handled, _ := run(15)
+		Expect(handled).To(HaveLen(15))
+
+		checkpoint, ok, err := checkpointer.Load(ctx, "sum")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(checkpoint.Offset).To(Equal(10))
+
+		handled, store := run(-1)
+		Expect(handled).To(ConsistOf(10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24))
+
+		total, _, _ := store.Load(ctx, "total")
+		Expect(total).To(Equal(300))
+
+		checkpoint, _, _ = checkpointer.Load(ctx, "sum")
+		Expect(checkpoint.Offset).To(Equal(25))
+
+		handled, _ = run(-1)
+		Expect(handled).To(BeEmpty())
+
+		Expect(checkpointer.Delete(ctx, "sum")).Should(Succeed())
+
+		handled, _ = run(-1)
+		Expect(handled).To(HaveLen(25))
+	})
+
+	It("should handle every payload within single execution", func() {
+		checkpointer := pipelines.NewFileCheckpointer(filepath.Join(GinkgoT().TempDir(), "checkpoints"))
+		parity := func(yield func(int) bool) {
+			for i := 0; i < 9; i++ {
+				if !yield(i % 2) {
+					return
+				}
+			}
+		}
+
+		taken := pipelines.Take(pipelines.Distinct(pipelines.PassThrough[int]().Pipeline()), 2)
+
+		expected := []int{}
+		for value, err := range taken.HandleSeq(ctx, parity) {
+			Expect(err).ShouldNot(HaveOccurred())
+			expected = append(expected, value)
+		}
+
+		handled := []int{}
+		for value, err := range taken.HandleCheckpointed(
+			ctx, "take", checkpointer, parity, pipelines.WithCheckpointInterval(3),
+		) {
+			Expect(err).ShouldNot(HaveOccurred())
+			handled = append(handled, value)
+		}
+
+		Expect(handled).To(Equal(expected))
+		Expect(handled).To(Equal([]int{0, 1}))
+
+		batched := pipelines.Batch(pipelines.PassThrough[int]().Pipeline(), 4, 0)
+
+		batches := [][]int{}
+		for batch, err := range batched.HandleCheckpointed(
+			ctx, "batch", checkpointer, payloads(9), pipelines.WithCheckpointInterval(3),
+		) {
+			Expect(err).ShouldNot(HaveOccurred())
+			batches = append(batches, batch)
+		}
+
+		Expect(batches).To(Equal([][]int{{0, 1, 2, 3}, {4, 5, 6, 7}, {8}}))
+
+		checkpoint, _, _ := checkpointer.Load(ctx, "batch")
+		Expect(checkpoint.Offset).To(Equal(9))
+	})
+})
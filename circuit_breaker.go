@@ -0,0 +1,312 @@
+package pipelines
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// State of Breaker.
+type CircuitState int
+
+const (
+	// Calls are allowed, failures are counted.
+	CircuitClosed CircuitState = iota
+	// Calls are rejected with ErrCircuitOpen until cooldown elapses.
+	CircuitOpen
+	// Limited number of trial calls is allowed to decide whether to close the circuit.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerSettings configures Breaker.
+type BreakerSettings struct {
+	// Number of calls in closed state before failure ratio is evaluated. Defaults to 1.
+	MinRequests int
+	// Ratio of failed calls that opens the circuit. Defaults to 0.5.
+	FailureRatio float64
+	// Period after which calls counted in closed state are reset. Calls are never reset if zero.
+	Window time.Duration
+	// How long circuit stays open before allowing trial calls.
+	Cooldown time.Duration
+	// Number of trial calls in half-open state. All of them should succeed to close the circuit. Defaults to 1.
+	HalfOpenRequests int
+	// Reports whether error counts as failure. Every error does if nil.
+	IsFailure func(error) bool
+	// Called after Breaker changes its state.
+	OnStateChange func(from, to CircuitState)
+}
+
+// Returns Breaker in closed state.
+func NewBreaker(settings BreakerSettings) *Breaker {
+	if settings.MinRequests < 1 {
+		settings.MinRequests = 1
+	}
+
+	if settings.FailureRatio <= 0 {
+		settings.FailureRatio = 0.5
+	}
+
+	if settings.HalfOpenRequests < 1 {
+		settings.HalfOpenRequests = 1
+	}
+
+	return &Breaker{settings: settings, windowStart: time.Now()}
+}
+
+// Breaker stops calls to failing dependency, letting it recover.
+// It is safe for concurrent use and can be shared by several Handles and stages.
+type Breaker struct {
+	settings BreakerSettings
+
+	mu          sync.Mutex
+	state       CircuitState
+	generation  uint64
+	requests    int
+	failures    int
+	successes   int
+	windowStart time.Time
+	openedAt    time.Time
+}
+
+type stateChange struct {
+	from, to CircuitState
+}
+
+// Returns current Breaker state.
+func (b *Breaker) State() CircuitState {
+	b.mu.Lock()
+	changes := b.refresh(time.Now())
+	state := b.state
+	b.mu.Unlock()
+
+	b.notify(changes)
+
+	return state
+}
+
+// Returns function to report call result with if call is allowed, or ErrCircuitOpen.
+func (b *Breaker) allow() (func(error), error) {
+	b.mu.Lock()
+	changes := b.refresh(time.Now())
+
+	if b.state == CircuitOpen ||
+		(b.state == CircuitHalfOpen && b.requests >= b.settings.HalfOpenRequests) {
+		b.mu.Unlock()
+		b.notify(changes)
+
+		return nil, ErrCircuitOpen
+	}
+
+	b.requests++
+	generation := b.generation
+	b.mu.Unlock()
+
+	b.notify(changes)
+
+	var once sync.Once
+
+	return func(err error) {
+		once.Do(func() { b.done(generation, err) })
+	}, nil
+}
+
+// Reports panic of call allowed by Breaker to done as failure and passes panic on.
+// Should be deferred.
+func reportPanic(done func(error)) {
+	if r := recover(); r != nil {
+		done(newPanicError(r))
+
+		panic(r)
+	}
+}
+
+func (b *Breaker) done(generation uint64, err error) {
+	b.mu.Lock()
+	now := time.Now()
+	changes := b.refresh(now)
+
+	// call was allowed before the last state change
+	if generation != b.generation {
+		b.mu.Unlock()
+		b.notify(changes)
+
+		return
+	}
+
+	failed := err != nil && (b.settings.IsFailure == nil || b.settings.IsFailure(err))
+
+	switch b.state {
+	case CircuitClosed:
+		if failed {
+			b.failures++
+		}
+
+		if b.requests >= b.settings.MinRequests &&
+			float64(b.failures)/float64(b.requests) >= b.settings.FailureRatio {
+			changes = append(changes, b.setState(CircuitOpen, now))
+		}
+	case CircuitHalfOpen:
+		if failed {
+			changes = append(changes, b.setState(CircuitOpen, now))
+
+			break
+		}
+
+		b.successes++
+		if b.successes >= b.settings.HalfOpenRequests {
+			changes = append(changes, b.setState(CircuitClosed, now))
+		}
+	}
+
+	b.mu.Unlock()
+	b.notify(changes)
+}
+
+// Moves Breaker to the state it should be in at now. Should be called with mu locked.
+func (b *Breaker) refresh(now time.Time) []stateChange {
+	switch b.state {
+	case CircuitOpen:
+		if now.Sub(b.openedAt) >= b.settings.Cooldown {
+			return []stateChange{b.setState(CircuitHalfOpen, now)}
+		}
+	case CircuitClosed:
+		if b.settings.Window > 0 && now.Sub(b.windowStart) >= b.settings.Window {
+			b.generation++
+			b.requests, b.failures = 0, 0
+			b.windowStart = now
+		}
+	}
+
+	return nil
+}
+
+// Should be called with mu locked.
+func (b *Breaker) setState(to CircuitState, now time.Time) stateChange {
+	change := stateChange{from: b.state, to: to}
+
+	b.state = to
+	b.generation++
+	b.requests, b.failures, b.successes = 0, 0, 0
+	b.windowStart = now
+
+	if to == CircuitOpen {
+		b.openedAt = now
+	}
+
+	return change
+}
+
+func (b *Breaker) notify(changes []stateChange) {
+	if b.settings.OnStateChange == nil {
+		return
+	}
+
+	for _, change := range changes {
+		b.settings.OnStateChange(change.from, change.to)
+	}
+}
+
+// Returns Handle that calls h only while b allows it and reports results of h to b.
+// Rejected calls return ErrCircuitOpen.
+func CircuitBreaker[T, U any](h Handle[T, U], b *Breaker) Handle[T, U] {
+	return func(ctx context.Context, payload T) (U, error) {
+		done, err := b.allow()
+		if err != nil {
+			return zero[U](), err
+		}
+
+		defer reportPanic(done)
+
+		v, err := h(ctx, payload)
+		done(err)
+
+		return v, err
+	}
+}
+
+// Option that makes stage call handler only while b allows it.
+// Handler call fails if handler writes an error.
+// Rejected events are passed on as Error with ErrCircuitOpen and the payload.
+func WithCircuitBreaker(b *Breaker) HandlerOptions {
+	return func(o handlerOptions) handlerOptions {
+		o.breaker = b
+
+		return o
+	}
+}
+
+func withCircuitBreaker[T, U any](handle Handler[T, U], b *Breaker) Handler[T, U] {
+	return func(ctx context.Context, w EventWriter[U], payload T) {
+		done, err := b.allow()
+		if err != nil {
+			w.WriteError(NewError(err, payload))
+
+			return
+		}
+
+		defer reportPanic(done)
+
+		bw := &breakerWriter[U]{EventWriter: w}
+		handle(ctx, bw, payload)
+
+		done(bw.firstError())
+	}
+}
+
+// breakerWriter remembers the first error handler writes.
+type breakerWriter[T any] struct {
+	EventWriter[T]
+
+	mu  sync.Mutex
+	err error
+}
+
+func (w *breakerWriter[T]) WriteError(err error) {
+	w.remember(err)
+	w.EventWriter.WriteError(err)
+}
+
+func (w *breakerWriter[T]) writeErrorContext(ctx context.Context, err error) error {
+	w.remember(err)
+
+	return writeErrorContext(ctx, w.EventWriter, err)
+}
+
+func (w *breakerWriter[T]) remember(err error) {
+	w.mu.Lock()
+	if w.err == nil {
+		w.err = err
+	}
+	w.mu.Unlock()
+}
+
+func (w *breakerWriter[T]) TryWrite(e T) error {
+	return asContextWriter(w.EventWriter).TryWrite(e)
+}
+
+func (w *breakerWriter[T]) WriteContext(ctx context.Context, e T) error {
+	return asContextWriter(w.EventWriter).WriteContext(ctx, e)
+}
+
+func (w *breakerWriter[T]) firstError() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.err
+}
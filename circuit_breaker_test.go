@@ -0,0 +1,175 @@
+package pipelines_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CircuitBreaker", func() {
+	ctx := context.TODO()
+
+	payloads := func(n int) func(yield func(int) bool) {
+		return func(yield func(int) bool) {
+			for i := 0; i < n; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+	}
+
+	It("should open after failure ratio is reached and close after successful trial", func() {
+		var (
+			mu      sync.Mutex
+			changes []string
+			failing atomic.Bool
+			calls   atomic.Int64
+		)
+
+		b := pipelines.NewBreaker(pipelines.BreakerSettings{
+			MinRequests:  4,
+			FailureRatio: 0.5,
+			Cooldown:     time.Millisecond * 20,
+			OnStateChange: func(from, to pipelines.CircuitState) {
+				mu.Lock()
+				changes = append(changes, fmt.Sprintf("%s->%s", from, to))
+				mu.Unlock()
+			},
+		})
+
+		h := pipelines.CircuitBreaker(func(ctx context.Context, e int) (int, error) {
+			calls.Add(1)
+			if failing.Load() {
+				return 0, fmt.Errorf("down")
+			}
+
+			return e, nil
+		}, b)
+
+		failing.Store(true)
+		for i := 0; i < 4; i++ {
+			_, err := h(ctx, i)
+			Expect(err).To(MatchError("down"))
+		}
+
+		Expect(b.State()).To(Equal(pipelines.CircuitOpen))
+
+		_, err := h(ctx, 5)
+		Expect(err).To(MatchError(pipelines.ErrCircuitOpen))
+		Expect(calls.Load()).To(Equal(int64(4)))
+
+		time.Sleep(time.Millisecond * 25)
+		Expect(b.State()).To(Equal(pipelines.CircuitHalfOpen))
+
+		failing.Store(false)
+		v, err := h(ctx, 6)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(v).To(Equal(6))
+		Expect(b.State()).To(Equal(pipelines.CircuitClosed))
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(changes).To(Equal([]string{"closed->open", "open->half-open", "half-open->closed"}))
+	})
+
+	It("should reopen when trial call fails", func() {
+		b := pipelines.NewBreaker(pipelines.BreakerSettings{Cooldown: time.Millisecond * 10})
+		h := pipelines.CircuitBreaker(func(ctx context.Context, e int) (int, error) {
+			return 0, fmt.Errorf("down")
+		}, b)
+
+		_, err := h(ctx, 0)
+		Expect(err).To(MatchError("down"))
+		Expect(b.State()).To(Equal(pipelines.CircuitOpen))
+
+		time.Sleep(time.Millisecond * 15)
+
+		_, err = h(ctx, 1)
+		Expect(err).To(MatchError("down"))
+		Expect(b.State()).To(Equal(pipelines.CircuitOpen))
+	})
+
+	It("should count panic of trial call as failure", func() {
+		b := pipelines.NewBreaker(pipelines.BreakerSettings{Cooldown: time.Millisecond * 10})
+
+		var panicking atomic.Bool
+		h := pipelines.CircuitBreaker(func(ctx context.Context, e int) (int, error) {
+			if panicking.Load() {
+				panic("oh no...")
+			}
+
+			return 0, fmt.Errorf("down")
+		}, b)
+
+		_, err := h(ctx, 0)
+		Expect(err).To(MatchError("down"))
+		Expect(b.State()).To(Equal(pipelines.CircuitOpen))
+
+		time.Sleep(time.Millisecond * 15)
+
+		panicking.Store(true)
+		Expect(func() { _, _ = h(ctx, 1) }).To(PanicWith("oh no..."))
+		Expect(b.State()).To(Equal(pipelines.CircuitOpen))
+
+		time.Sleep(time.Millisecond * 15)
+
+		panicking.Store(false)
+		Expect(b.State()).To(Equal(pipelines.CircuitHalfOpen))
+
+		_, err = h(ctx, 2)
+		Expect(err).To(MatchError("down"))
+	})
+
+	It("should ignore errors that are not failures", func() {
+		b := pipelines.NewBreaker(pipelines.BreakerSettings{
+			IsFailure: func(err error) bool { return err.Error() != "bad input" },
+		})
+		h := pipelines.CircuitBreaker(func(ctx context.Context, e int) (int, error) {
+			return 0, fmt.Errorf("bad input")
+		}, b)
+
+		for i := 0; i < 5; i++ {
+			_, err := h(ctx, i)
+			Expect(err).To(MatchError("bad input"))
+		}
+
+		Expect(b.State()).To(Equal(pipelines.CircuitClosed))
+	})
+
+	It("should reject stage events with ErrCircuitOpen and payload", func() {
+		var calls atomic.Int64
+
+		b := pipelines.NewBreaker(pipelines.BreakerSettings{MinRequests: 2, Cooldown: time.Hour})
+		failing := func(ctx context.Context, w pipelines.EventWriter[int], e int) {
+			calls.Add(1)
+			w.WriteError(pipelines.NewError(fmt.Errorf("down"), e))
+		}
+
+		c := pipelines.Pipe(
+			pipelines.PassThrough[int]().Pipeline(),
+			failing,
+			pipelines.WithCircuitBreaker(b),
+			pipelines.WithOrderPreserved(),
+		)
+
+		open := []int{}
+		for _, err := range c.HandleSeq(ctx, payloads(10)) {
+			Expect(err).Should(HaveOccurred())
+
+			if errors.Is(err, pipelines.ErrCircuitOpen) {
+				open = append(open, err.(*pipelines.Error[int]).Payload)
+			}
+		}
+
+		Expect(calls.Load()).To(Equal(int64(2)))
+		Expect(open).To(Equal([]int{2, 3, 4, 5, 6, 7, 8, 9}))
+	})
+})
@@ -0,0 +1,118 @@
+package pipelines
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// Handler that writes result of fn for every payload.
+func Map[T, U any](fn func(T) U) Handler[T, U] {
+	return func(ctx context.Context, w EventWriter[U], payload T) {
+		w.Write(fn(payload))
+	}
+}
+
+// Handler that writes only payloads keep reports true for.
+func Filter[T any](keep func(T) bool) Handler[T, T] {
+	return func(ctx context.Context, w EventWriter[T], payload T) {
+		if keep(payload) {
+			w.Write(payload)
+		}
+	}
+}
+
+// Handler that writes every element of sequence fn returns for payload as separate event.
+// Iteration stops once Pipeline execution is done.
+func FlatMap[T, U any](fn func(T) iter.Seq[U]) Handler[T, U] {
+	return func(ctx context.Context, w EventWriter[U], payload T) {
+		for v := range fn(payload) {
+			if ctx.Err() != nil {
+				return
+			}
+
+			w.Write(v)
+		}
+	}
+}
+
+// Handler that calls fn with every payload and writes payload without changes.
+func Tap[T any](fn func(T)) Handler[T, T] {
+	return func(ctx context.Context, w EventWriter[T], payload T) {
+		fn(payload)
+		w.Write(payload)
+	}
+}
+
+// Returns `Pipeline[T, U]` that passes on only first occurrence of every event of p
+// within single Pipeline execution.
+// Errors are passed on as they arrive.
+func Distinct[T any, U comparable](p Pipeline[T, U]) Pipeline[T, U] {
+	return func(ctx context.Context) (EventWriterCloser[T], EventReader[U], int) {
+		w, r, pool := p(ctx)
+
+		var mu sync.Mutex
+		seen := make(map[U]struct{})
+		distinct := func(ctx context.Context, w EventWriter[U], payload U) {
+			mu.Lock()
+			_, ok := seen[payload]
+			seen[payload] = struct{}{}
+			mu.Unlock()
+
+			if !ok {
+				w.Write(payload)
+			}
+		}
+
+		return w, startWorkers(ctx, distinct, r, newHandlerOptions([]HandlerOptions{WithHandlerPool(pool)})), pool
+	}
+}
+
+// Returns `Pipeline[T, U]` that passes on first n events of p within single Pipeline execution.
+// Once n events are passed on p is stopped and its remaining events and errors are discarded.
+// Errors that arrive before that are passed on and are not counted.
+func Take[T, U any](p Pipeline[T, U], n int) Pipeline[T, U] {
+	return func(ctx context.Context) (EventWriterCloser[T], EventReader[U], int) {
+		upstream, cancel := context.WithCancel(ctx)
+
+		w, r, pool := p(upstream)
+		rw := newEventRW[U](ctx, 0)
+		out := rw.GetWriter()
+
+		go func() {
+			stop := func() {
+				out.Close()
+				cancel()
+			}
+
+			defer stop()
+
+			if n <= 0 {
+				stop()
+			}
+
+			taken := 0
+			for event := range r.Read() {
+				// discard events until p is stopped
+				if taken >= n {
+					event.ack.done(nil)
+
+					continue
+				}
+
+				passOn(out, event)
+				if event.Err != nil {
+					continue
+				}
+
+				r.Dispose(event)
+
+				if taken++; taken == n {
+					stop()
+				}
+			}
+		}()
+
+		return w, rw, pool
+	}
+}
@@ -0,0 +1,114 @@
+package pipelines_test
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/goleak"
+)
+
+var _ = Describe("Combinators", func() {
+	ctx := context.TODO()
+
+	It("should map, filter, flat map and tap events", func() {
+		var tapped atomic.Int64
+
+		c := pipelines.Pipe4(
+			pipelines.PassThrough[int]().Pipeline(),
+			pipelines.Filter(func(e int) bool { return e%2 == 0 }),
+			pipelines.FlatMap(func(e int) iter.Seq[int] { return slices.Values([]int{e, e}) }),
+			pipelines.Tap(func(int) { tapped.Add(1) }),
+			pipelines.Map(strconv.Itoa),
+		)
+
+		accumulated := []string{}
+		for value, err := range c.HandleSeq(ctx, slices.Values([]int{1, 2, 3, 4})) {
+			Expect(err).ShouldNot(HaveOccurred())
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(ConsistOf("2", "2", "4", "4"))
+		Expect(tapped.Load()).To(Equal(int64(4)))
+	})
+
+	It("should pass on distinct events within execution", func() {
+		c := pipelines.Distinct(pipelines.PassThrough[int]().Pipeline(pipelines.WithHandlerPool(4)))
+
+		for range 2 {
+			accumulated := []int{}
+			for value, err := range c.HandleSeq(ctx, slices.Values([]int{1, 2, 1, 3, 2, 1})) {
+				Expect(err).ShouldNot(HaveOccurred())
+				accumulated = append(accumulated, value)
+			}
+
+			Expect(accumulated).To(ConsistOf(1, 2, 3))
+		}
+	})
+
+	It("should take first events and stop upstream", func() {
+		var (
+			handled  atomic.Int64
+			finished atomic.Bool
+		)
+
+		infinite := func(yield func(int) bool) {
+			defer finished.Store(true)
+
+			for i := 0; ; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+		count := func(ctx context.Context, w pipelines.EventWriter[int], e int) {
+			handled.Add(1)
+			if e == 1 {
+				w.WriteError(fmt.Errorf("one"))
+
+				return
+			}
+
+			w.Write(e)
+		}
+
+		c := pipelines.Take(pipelines.Handler[int, int](count).Pipeline(), 3)
+
+		accumulated := []int{}
+		errs := 0
+		for value, err := range c.HandleSeq(ctx, infinite) {
+			if err != nil {
+				errs++
+
+				continue
+			}
+
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(Equal([]int{0, 2, 3}))
+		Expect(errs).To(Equal(1))
+		Expect(handled.Load()).To(BeNumerically("<", 10))
+		// execution returns once it stops iterating input
+		Expect(finished.Load()).To(BeTrue())
+
+		time.Sleep(time.Millisecond * 50)
+
+		err := goleak.Find(
+			goleak.IgnoreTopFunction("github.com/onsi/ginkgo/v2/internal.(*Suite).runNode"),
+			goleak.IgnoreTopFunction(
+				"github.com/onsi/ginkgo/v2/internal/interrupt_handler.(*InterruptHandler).registerForInterrupts.func2",
+			),
+			goleak.IgnoreAnyFunction("github.com/onsi/ginkgo/v2/internal.RegisterForProgressSignal.func1"),
+		)
+
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,150 @@
+package pipelines
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"sync"
+	"time"
+)
+
+// DeadLetter stores payloads that failed to be processed.
+type DeadLetter[T any] interface {
+	// Stores failed payload along with the error.
+	Put(ctx context.Context, err *Error[T]) error
+}
+
+// Returns ErrorHandler that sends errors carrying payload of type T to dl.
+// Other errors are passed on unchanged, as well as errors dl failed to store.
+func DeadLetterHandler[T any](dl DeadLetter[T]) ErrorHandler {
+	return func(ctx context.Context, w ErrorWriter, err error) {
+		var e *Error[T]
+		if !errors.As(err, &e) {
+			w.WriteError(err)
+
+			return
+		}
+
+		if putErr := dl.Put(ctx, e); putErr != nil {
+			w.WriteError(fmt.Errorf("%w: failed to store dead letter: %w", err, putErr))
+		}
+	}
+}
+
+// Option that routes errors carrying payload of type T to dl instead of passing them on.
+func WithDeadLetter[T any](dl DeadLetter[T]) HandlerOptions {
+	return WithErrorHandler(DeadLetterHandler(dl))
+}
+
+// Adds stage that routes errors carrying payload of type N to dl to the `Pipeline[T, U]`.
+func PipeDeadLetter[T, U, N any](p Pipeline[T, U], dl DeadLetter[N]) Pipeline[T, U] {
+	return PipeErrorHandler(p, DeadLetterHandler(dl))
+}
+
+// Returns DeadLetter that keeps failed payloads in memory.
+func NewMemoryDeadLetter[T any]() *MemoryDeadLetter[T] {
+	return &MemoryDeadLetter[T]{}
+}
+
+// DeadLetter that keeps failed payloads in memory.
+type MemoryDeadLetter[T any] struct {
+	mu      sync.Mutex
+	letters []*Error[T]
+}
+
+func (dl *MemoryDeadLetter[T]) Put(_ context.Context, err *Error[T]) error {
+	dl.mu.Lock()
+	dl.letters = append(dl.letters, err)
+	dl.mu.Unlock()
+
+	return nil
+}
+
+// Returns errors stored so far.
+func (dl *MemoryDeadLetter[T]) Letters() []*Error[T] {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	return append([]*Error[T](nil), dl.letters...)
+}
+
+// Entry of JSON-lines dead letter.
+type DeadLetterRecord[T any] struct {
+	Payload  T         `json:"payload"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts,omitempty"`
+	Stage    string    `json:"stage,omitempty"`
+	Class    string    `json:"class,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// Returns DeadLetter that writes failed payloads to w as JSON lines.
+func NewJSONLinesDeadLetter[T any](w io.Writer) *JSONLinesDeadLetter[T] {
+	return &JSONLinesDeadLetter[T]{encoder: json.NewEncoder(w), w: w}
+}
+
+// Returns DeadLetter that appends failed payloads to the file at path as JSON lines.
+// File is created if it does not exist.
+func OpenJSONLinesDeadLetter[T any](path string) (*JSONLinesDeadLetter[T], error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewJSONLinesDeadLetter[T](f), nil
+}
+
+// DeadLetter that writes failed payloads as JSON lines.
+type JSONLinesDeadLetter[T any] struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	w       io.Writer
+}
+
+func (dl *JSONLinesDeadLetter[T]) Put(_ context.Context, err *Error[T]) error {
+	record := DeadLetterRecord[T]{
+		Payload:  err.Payload,
+		Error:    err.Error(),
+		Attempts: err.Attempts,
+		Stage:    err.Stage,
+		Class:    err.errorClass().String(),
+		Time:     err.Time,
+	}
+
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	return dl.encoder.Encode(record)
+}
+
+// Closes underlying writer if it is an io.Closer.
+func (dl *JSONLinesDeadLetter[T]) Close() error {
+	if c, ok := dl.w.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
+
+// Reads records written by JSONLinesDeadLetter from r.
+func ReadDeadLetters[T any](r io.Reader) iter.Seq2[DeadLetterRecord[T], error] {
+	return func(yield func(DeadLetterRecord[T], error) bool) {
+		decoder := json.NewDecoder(r)
+		for {
+			var record DeadLetterRecord[T]
+
+			err := decoder.Decode(&record)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if !yield(record, err) || err != nil {
+				return
+			}
+		}
+	}
+}
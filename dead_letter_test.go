@@ -0,0 +1,106 @@
+package pipelines_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeadLetter", func() {
+	ctx := context.TODO()
+	errOdd := errors.New("odd")
+
+	failOdd := pipelines.HandleFunc(
+		pipelines.LiftErr(func(_ context.Context, n int) error {
+			if n%2 == 1 {
+				return errOdd
+			}
+
+			return nil
+		}),
+	)
+
+	payloads := func(yield func(int) bool) {
+		for i := 0; i < 6; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	It("should route failed payloads to dead letter", func() {
+		dl := pipelines.NewMemoryDeadLetter[int]()
+		c := pipelines.Pipe(
+			pipelines.Pipe(pipelines.PassThrough[int]().Pipeline(), failOdd),
+			pipelines.PassThrough[int](),
+			pipelines.WithDeadLetter(dl),
+		)
+
+		accumulated := []int{}
+		for value, err := range c.HandleSeq(ctx, payloads) {
+			Expect(err).ShouldNot(HaveOccurred())
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(ConsistOf(0, 2, 4))
+
+		payloads := []int{}
+		for _, letter := range dl.Letters() {
+			Expect(letter).Should(MatchError(errOdd))
+			payloads = append(payloads, letter.Payload)
+		}
+
+		Expect(payloads).To(ConsistOf(1, 3, 5))
+	})
+
+	It("should pass on errors with other payload types", func() {
+		dl := pipelines.NewMemoryDeadLetter[string]()
+		c := pipelines.PipeDeadLetter(pipelines.Pipe(pipelines.PassThrough[int]().Pipeline(), failOdd), dl)
+
+		errs := 0
+		for _, err := range c.HandleSeq(ctx, payloads) {
+			if err != nil {
+				Expect(err).Should(MatchError(errOdd))
+				errs++
+			}
+		}
+
+		Expect(errs).To(Equal(3))
+		Expect(dl.Letters()).To(BeEmpty())
+	})
+
+	It("should write dead letters to JSON-lines file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "dead_letters.jsonl")
+
+		dl, err := pipelines.OpenJSONLinesDeadLetter[int](path)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		c := pipelines.PipeDeadLetter(pipelines.Pipe(pipelines.PassThrough[int]().Pipeline(), failOdd), dl)
+		for _, err := range c.HandleSeq(ctx, payloads) {
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		Expect(dl.Close()).Should(Succeed())
+
+		f, err := os.Open(path)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		defer f.Close()
+
+		payloads := []int{}
+		for record, err := range pipelines.ReadDeadLetters[int](f) {
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(record.Error).To(Equal("error processing int: odd"))
+			Expect(record.Class).To(Equal("permanent"))
+			Expect(record.Time).NotTo(BeZero())
+			payloads = append(payloads, record.Payload)
+		}
+
+		Expect(payloads).To(ConsistOf(1, 3, 5))
+	})
+})
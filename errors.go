@@ -1,24 +1,232 @@
 package pipelines
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"runtime/debug"
+	"time"
 )
 
 // Returns error with cause and payload.
 func NewError[T any](cause error, payload T) error {
-	return &Error[T]{cause: cause, Payload: payload}
+	return newError(cause, payload, 0)
+}
+
+func newError[T any](cause error, payload T, attempts int) *Error[T] {
+	return &Error[T]{cause: cause, Payload: payload, Attempts: attempts, Time: time.Now(), Class: classify(cause)}
 }
 
 type Error[T any] struct {
 	cause error
 
 	Payload T
+	// Number of attempts made to process Payload by Retry or stage with WithRetry,
+	// including the first one, even if it was not retried.
+	// Zero if Payload was not processed with RetryPolicy.
+	Attempts int
+	// Name of the stage that failed, set with WithName.
+	// Empty if stage is not named.
+	Stage string
+	// Time error occurred.
+	Time time.Time
+	// Classification of the cause.
+	Class ErrorClass
 }
 
 func (err *Error[T]) Error() string {
+	if err.Attempts > 1 {
+		return fmt.Sprintf("error processing %T after %d attempts: %s", err.Payload, err.Attempts, err.cause)
+	}
+
 	return fmt.Sprintf("error processing %T: %s", err.Payload, err.cause)
 }
 
 func (err *Error[T]) Unwrap() error {
 	return err.cause
 }
+
+func (err *Error[T]) errorClass() ErrorClass {
+	if err.Class == ClassUnknown {
+		return classify(err.cause)
+	}
+
+	return err.Class
+}
+
+func (err *Error[T]) stage() string {
+	return err.Stage
+}
+
+// Returns copy of err failed at stage, unless stage is already known.
+func (err *Error[T]) atStage(stage string) error {
+	if err.Stage != "" {
+		return err
+	}
+
+	atStage := *err
+	atStage.Stage = stage
+
+	return &atStage
+}
+
+// Returns payload of Error[T] in err chain.
+func PayloadOf[T any](err error) (T, bool) {
+	var e *Error[T]
+	if errors.As(err, &e) {
+		return e.Payload, true
+	}
+
+	return zero[T](), false
+}
+
+// Returns name of the stage err occurred at.
+// Empty if err is not Error or stage is not named.
+func StageOf(err error) string {
+	var e interface{ stage() string }
+	if errors.As(err, &e) {
+		return e.stage()
+	}
+
+	return ""
+}
+
+// Classification of error telling whether handling should be retried.
+type ErrorClass int
+
+const (
+	// Class is derived from the cause.
+	ClassUnknown ErrorClass = iota
+	// Handling might succeed if retried.
+	ClassTransient
+	// Handling will fail again if retried.
+	ClassPermanent
+	// Handling was stopped because context is done.
+	ClassCancelled
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ClassTransient:
+		return "transient"
+	case ClassPermanent:
+		return "permanent"
+	case ClassCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// Marks err as transient.
+func Transient(err error) error {
+	return &classifiedError{error: err, class: ClassTransient}
+}
+
+// Marks err as permanent.
+func Permanent(err error) error {
+	return &classifiedError{error: err, class: ClassPermanent}
+}
+
+type classifiedError struct {
+	error
+
+	class ErrorClass
+}
+
+func (err *classifiedError) Unwrap() error {
+	return err.error
+}
+
+func (err *classifiedError) errorClass() ErrorClass {
+	return err.class
+}
+
+// Returns class of err.
+// Errors marked with Transient or Permanent keep their class.
+// Otherwise errors caused by done context are cancelled,
+// timeouts, open circuit and full buffer are transient,
+// and every other error is permanent.
+func Classify(err error) ErrorClass {
+	if err == nil {
+		return ClassUnknown
+	}
+
+	var c interface{ errorClass() ErrorClass }
+	if errors.As(err, &c) {
+		return c.errorClass()
+	}
+
+	return classify(err)
+}
+
+func classify(err error) ErrorClass {
+	var (
+		c       *classifiedError
+		timeout interface{ Timeout() bool }
+	)
+
+	switch {
+	case err == nil:
+		return ClassUnknown
+	case errors.As(err, &c):
+		return c.class
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return ClassCancelled
+	case errors.Is(err, ErrHandlerTimeout),
+		errors.Is(err, ErrCircuitOpen),
+		errors.Is(err, ErrBufferFull),
+		errors.Is(err, ErrWorkerBusy),
+		errors.As(err, &timeout) && timeout.Timeout():
+		return ClassTransient
+	default:
+		return ClassPermanent
+	}
+}
+
+// Reports whether err is marked with Permanent.
+func isMarkedPermanent(err error) bool {
+	var c *classifiedError
+
+	return errors.As(err, &c) && c.class == ClassPermanent
+}
+
+// Reports whether err is transient.
+func IsTransient(err error) bool {
+	return Classify(err) == ClassTransient
+}
+
+// Reports whether err is permanent.
+func IsPermanent(err error) bool {
+	return Classify(err) == ClassPermanent
+}
+
+// Reports whether err is caused by done context.
+func IsCancelled(err error) bool {
+	return Classify(err) == ClassCancelled
+}
+
+// PanicError is the cause of Error written when handler panics.
+type PanicError struct {
+	// Value passed to panic.
+	Value any
+	// Stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func newPanicError(value any) *PanicError {
+	return &PanicError{Value: value, Stack: debug.Stack()}
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("recovered from panic: %v", err.Value)
+}
+
+// Returns Value if it is an error.
+func (err *PanicError) Unwrap() error {
+	if e, ok := err.Value.(error); ok {
+		return e
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package pipelines_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Error", func() {
+	ctx := context.TODO()
+
+	It("should classify errors", func() {
+		Expect(pipelines.IsPermanent(fmt.Errorf("some error"))).To(BeTrue())
+		Expect(pipelines.IsCancelled(fmt.Errorf("stopped: %w", context.Canceled))).To(BeTrue())
+		Expect(pipelines.IsTransient(pipelines.NewError(pipelines.ErrHandlerTimeout, 1))).To(BeTrue())
+		Expect(pipelines.IsTransient(pipelines.Transient(fmt.Errorf("unavailable")))).To(BeTrue())
+		Expect(pipelines.IsPermanent(pipelines.NewError(pipelines.Permanent(pipelines.ErrCircuitOpen), 1))).To(BeTrue())
+		Expect(pipelines.Classify(nil)).To(Equal(pipelines.ClassUnknown))
+		Expect(pipelines.IsTransient(pipelines.Transient(context.DeadlineExceeded))).To(BeTrue())
+		Expect(pipelines.IsPermanent(pipelines.NewError(pipelines.Permanent(context.Canceled), 1))).To(BeTrue())
+
+		err := pipelines.NewError(pipelines.Transient(fmt.Errorf("unavailable")), 1)
+		Expect(err).To(MatchError("error processing int: unavailable"))
+		Expect(err.(*pipelines.Error[int]).Class).To(Equal(pipelines.ClassTransient))
+	})
+
+	It("should extract payload", func() {
+		err := fmt.Errorf("wrapped: %w", pipelines.NewError(fmt.Errorf("some error"), "payload"))
+
+		payload, ok := pipelines.PayloadOf[string](err)
+		Expect(ok).To(BeTrue())
+		Expect(payload).To(Equal("payload"))
+
+		_, ok = pipelines.PayloadOf[int](err)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should carry stage name, attempts and time", func() {
+		start := time.Now()
+		policy := pipelines.RetryPolicy{MaxAttempts: 2, Backoff: pipelines.ConstantBackoff(0)}
+		fail := func(ctx context.Context, e int) (int, error) {
+			return 0, pipelines.Transient(fmt.Errorf("unavailable"))
+		}
+
+		c := pipelines.Pipe(
+			pipelines.Pipe(
+				pipelines.PassThrough[int]().Pipeline(),
+				pipelines.HandleFunc(fail),
+				pipelines.WithName("parse"),
+				pipelines.WithRetry(policy),
+			),
+			pipelines.PassThrough[int](),
+			pipelines.WithName("next"),
+		)
+
+		for _, err := range c.Handle(ctx, 1) {
+			var e *pipelines.Error[int]
+			Expect(errors.As(err, &e)).To(BeTrue())
+			Expect(e.Stage).To(Equal("parse"))
+			Expect(pipelines.StageOf(err)).To(Equal("parse"))
+			Expect(e.Attempts).To(Equal(2))
+			Expect(e.Time).To(BeTemporally(">=", start))
+			Expect(pipelines.IsTransient(err)).To(BeTrue())
+		}
+	})
+
+	It("should not name nested stages of Pipe2 after the last one", func() {
+		fail := func(ctx context.Context, e int) (int, error) {
+			return 0, fmt.Errorf("failed")
+		}
+
+		c := pipelines.Pipe2(
+			pipelines.PassThrough[int]().Pipeline(),
+			pipelines.HandleFunc(fail),
+			pipelines.PassThrough[int](),
+			pipelines.WithName("second"),
+		)
+
+		for _, err := range c.Handle(ctx, 1) {
+			Expect(err).Should(HaveOccurred())
+			Expect(pipelines.StageOf(err)).To(BeEmpty())
+		}
+	})
+})
@@ -4,4 +4,7 @@ package pipelines
 type Event[T any] struct {
 	Payload T
 	Err     error
+
+	// ack of the payload Event is derived from, nil if it is not tracked
+	ack *ack
 }
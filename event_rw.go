@@ -2,8 +2,13 @@ package pipelines
 
 import (
 	"context"
+	"errors"
 	"sync"
-	"sync/atomic"
+)
+
+var (
+	ErrBufferFull   = errors.New("event buffer is full")
+	ErrWriterClosed = errors.New("event writer is closed")
 )
 
 // Serves to pass Events to Handlers.
@@ -27,10 +32,80 @@ type ErrorWriter interface {
 // Serves to write Events in Handle.Handle to chain Events.
 type EventWriter[T any] interface {
 	// Writes Event to a channel.
+	// Blocks until Event is accepted by the next stage or Pipeline execution is stopped.
 	Write(e T)
 	ErrorWriter
 }
 
+// Serves to write Events without waiting for the next stage indefinitely.
+// EventWriter passed to Handler by Pipeline stages implements it:
+//
+//	if cw, ok := w.(ContextWriter[T]); ok {
+//		err = cw.TryWrite(e)
+//	}
+//
+// Order-preserving stages collect events without limit,
+// so there TryWrite always succeeds and writes never wait for the next stage.
+type ContextWriter[T any] interface {
+	EventWriter[T]
+	// Writes Event to a channel only if it can be done without blocking.
+	// Returns ErrBufferFull if the next stage can not accept Event right away.
+	TryWrite(e T) error
+	// Writes Event to a channel.
+	// Returns an error if ctx or Pipeline execution is done before Event is accepted.
+	WriteContext(ctx context.Context, e T) error
+}
+
+// Returns w as ContextWriter.
+// If w does not implement it, its writes block as Write does.
+func asContextWriter[T any](w EventWriter[T]) ContextWriter[T] {
+	if cw, ok := w.(ContextWriter[T]); ok {
+		return cw
+	}
+
+	return blockingWriter[T]{EventWriter: w}
+}
+
+type blockingWriter[T any] struct {
+	EventWriter[T]
+}
+
+func (w blockingWriter[T]) TryWrite(e T) error {
+	w.Write(e)
+
+	return nil
+}
+
+func (w blockingWriter[T]) WriteContext(ctx context.Context, e T) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	w.Write(e)
+
+	return nil
+}
+
+// errorContextWriter writes errors giving up once ctx is done.
+type errorContextWriter interface {
+	writeErrorContext(ctx context.Context, err error) error
+}
+
+// Writes err to w. Gives up once ctx is done if w supports it, otherwise blocks as WriteError does.
+func writeErrorContext(ctx context.Context, w ErrorWriter, err error) error {
+	if ew, ok := w.(errorContextWriter); ok {
+		return ew.writeErrorContext(ctx, err)
+	}
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	w.WriteError(err)
+
+	return nil
+}
+
 // Serves to close EventWriter.
 type EventCloser interface {
 	// Signals that no more writes are expected.
@@ -42,10 +117,12 @@ type EventWriterCloser[T any] interface {
 	EventCloser
 }
 
-func newEventRW[T any](ctx context.Context) EventReader[T] {
+// Returns eventRW with the events channel able to hold buffer Events
+// before writers are blocked.
+func newEventRW[T any](ctx context.Context, buffer int) *eventRW[T] {
 	rw := &eventRW[T]{
 		ctx:           ctx,
-		eventsChannel: make(chan *Event[T]),
+		eventsChannel: make(chan *Event[T], buffer),
 		pool: &sync.Pool{
 			New: func() any {
 				return &Event[T]{}
@@ -82,71 +159,96 @@ func (r *eventRW[T]) GetWriter() EventWriterCloser[T] {
 		}()
 	})
 
-	events := make(chan *Event[T])
-	w := &eventW[T]{events: events, ctx: r.ctx, pool: r.pool}
-
-	go func() {
-		for e := range events {
-			r.eventsChannel <- e
-		}
-
-		r.writersGroup.Done()
-	}()
-
-	return w
+	return &eventW[T]{
+		ctx:    r.ctx,
+		pool:   r.pool,
+		events: r.eventsChannel,
+		done:   r.writersGroup.Done,
+	}
 }
 
 type eventW[T any] struct {
-	pool    *sync.Pool
-	ctx     context.Context
-	events  chan *Event[T]
-	writeWG sync.WaitGroup
-	once    sync.Once
-	isDone  atomic.Bool
+	pool   *sync.Pool
+	ctx    context.Context
+	events chan<- *Event[T]
+	done   func()
+	mu     sync.RWMutex
+	closed bool
 }
 
 func (w *eventW[T]) Write(e T) {
-	if !w.isDone.Load() {
-		w.writeWG.Add(1)
-		go func() {
-			select {
-			case <-w.ctx.Done():
-			default:
-				if !w.isDone.Load() {
-					event := w.pool.Get().(*Event[T])
-					event.Payload = e
-					w.events <- event
-				}
-			}
-
-			w.writeWG.Done()
-		}()
-	}
+	_ = w.WriteContext(w.ctx, e)
+}
+
+func (w *eventW[T]) TryWrite(e T) error {
+	return w.sendAcked(w.ctx, e, nil, false, nil)
+}
+
+func (w *eventW[T]) WriteContext(ctx context.Context, e T) error {
+	return w.sendAcked(ctx, e, nil, true, nil)
 }
 
 func (w *eventW[T]) WriteError(err error) {
-	if !w.isDone.Load() {
-		w.writeWG.Add(1)
-		go func() {
-			select {
-			case <-w.ctx.Done():
-			default:
-				if !w.isDone.Load() {
-					event := w.pool.Get().(*Event[T])
-					event.Err = err
-					w.events <- event
-				}
-			}
-
-			w.writeWG.Done()
-		}()
-	}
+	_ = w.sendAcked(w.ctx, zero[T](), err, true, nil)
+}
+
+func (w *eventW[T]) writeErrorContext(ctx context.Context, err error) error {
+	return w.sendAcked(ctx, zero[T](), err, true, nil)
 }
 
+// Closes writer after all in-flight writes are finished.
 func (w *eventW[T]) Close() {
-	go w.once.Do(func() {
-		w.writeWG.Wait()
-		w.isDone.Store(true)
-		close(w.events)
-	})
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return
+	}
+
+	w.closed = true
+	w.done()
+}
+
+// Sends Event with ack a, nil if there is none, to the events channel.
+// If wait is false sendAcked gives up as soon as the channel is not ready to accept Event.
+func (w *eventW[T]) sendAcked(ctx context.Context, payload T, err error, wait bool, a *ack) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return ErrWriterClosed
+	}
+
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+
+	event := w.pool.Get().(*Event[T])
+	event.Payload = payload
+	event.Err = err
+	event.ack = a
+
+	if !wait {
+		select {
+		case w.events <- event:
+			return nil
+		default:
+			w.pool.Put(event)
+
+			return ErrBufferFull
+		}
+	}
+
+	select {
+	case w.events <- event:
+		return nil
+	case <-w.ctx.Done():
+		w.pool.Put(event)
+
+		return w.ctx.Err()
+	case <-ctx.Done():
+		w.pool.Put(event)
+
+		return ctx.Err()
+	}
 }
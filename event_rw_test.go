@@ -0,0 +1,123 @@
+package pipelines_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EventWriter", func() {
+	ctx := context.TODO()
+
+	It("should block writes when stage buffer is full", func() {
+		var written atomic.Int64
+
+		producer := func(ctx context.Context, w pipelines.EventWriter[int], _ int) {
+			for i := 0; i < 100; i++ {
+				w.Write(i)
+				written.Add(1)
+			}
+		}
+
+		c := pipelines.Pipe(pipelines.PassThrough[int]().Pipeline(), producer, pipelines.WithBuffer(5))
+
+		count := 0
+		for _, err := range c.Handle(ctx, 0) {
+			Expect(err).ShouldNot(HaveOccurred())
+
+			if count == 0 {
+				time.Sleep(time.Millisecond * 50)
+
+				// one event is read, five are buffered and one more might be in flight
+				Expect(written.Load()).To(BeNumerically("<=", 7))
+			}
+
+			count++
+		}
+
+		Expect(count).To(Equal(100))
+		Expect(written.Load()).To(Equal(int64(100)))
+	})
+
+	It("should pass ContextWriter to handler of every stage", func() {
+		tryWrite := func(ctx context.Context, w pipelines.EventWriter[int], n int) {
+			cw, ok := w.(pipelines.ContextWriter[int])
+			if !ok {
+				w.WriteError(errors.New("not a ContextWriter"))
+
+				return
+			}
+
+			if err := cw.TryWrite(n); err != nil {
+				w.WriteError(err)
+			}
+		}
+
+		for _, opts := range [][]pipelines.HandlerOptions{
+			nil,
+			{pipelines.WithName("stage"), pipelines.WithObserver(pipelines.NopObserver{})},
+			{pipelines.WithTimeout(time.Second), pipelines.WithRetry(pipelines.RetryPolicy{MaxAttempts: 2})},
+			{pipelines.WithCircuitBreaker(pipelines.NewBreaker(pipelines.BreakerSettings{}))},
+			{pipelines.WithOrderPreserved()},
+		} {
+			// buffered, so TryWrite does not depend on reader being ready
+			c := pipelines.Pipe(pipelines.PassThrough[int]().Pipeline(), tryWrite, append(opts, pipelines.WithBuffer(1))...)
+
+			for _, err := range c.Handle(ctx, 1) {
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+		}
+	})
+
+	It("should not block on TryWrite and WriteContext", func() {
+		gate := make(chan struct{})
+		received := make(chan struct{})
+		errs := make(chan error, 3)
+
+		producer := func(ctx context.Context, w pipelines.EventWriter[int], _ int) {
+			defer close(gate)
+
+			w.Write(1)
+			<-received
+
+			cw := w.(pipelines.ContextWriter[int])
+
+			errs <- cw.TryWrite(2)
+			errs <- cw.TryWrite(3)
+
+			ctx, cancel := context.WithTimeout(ctx, time.Millisecond*10)
+			defer cancel()
+
+			errs <- cw.WriteContext(ctx, 4)
+		}
+		consumer := func(ctx context.Context, w pipelines.EventWriter[int], e int) {
+			if e == 1 {
+				close(received)
+			}
+
+			<-gate
+			w.Write(e)
+		}
+
+		c := pipelines.Pipe(
+			pipelines.Pipe(pipelines.PassThrough[int]().Pipeline(), producer, pipelines.WithBuffer(1)),
+			consumer,
+		)
+
+		accumulated := []int{}
+		for value, err := range c.Handle(ctx, 0) {
+			Expect(err).ShouldNot(HaveOccurred())
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(Equal([]int{1, 2}))
+		Expect(<-errs).Should(Succeed())
+		Expect(<-errs).Should(MatchError(pipelines.ErrBufferFull))
+		Expect(<-errs).Should(MatchError(context.DeadlineExceeded))
+	})
+})
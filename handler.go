@@ -2,45 +2,85 @@ package pipelines
 
 import (
 	"context"
-	"fmt"
+	"time"
 )
 
 // Handler is used to handle particular event.
 type Handler[T, U any] func(context.Context, EventWriter[U], T)
 
+// Returns Pipeline with h as its only stage configured by opts.
 func (h Handler[T, U]) Pipeline(opts ...HandlerOptions) Pipeline[T, U] {
-	h = withRecovery(h)
-	errHandler := defaultErrorHandler
-	pool := 0
-
-	for _, option := range opts {
-		errHandler, pool = option(errHandler, pool)
-	}
+	o := newStageOptions[T](opts)
 
 	return func(ctx context.Context) (EventWriterCloser[T], EventReader[U], int) {
-		rw := newEventRW[T](ctx)
+		rw := newEventRW[T](ctx, 0)
 
-		return rw.GetWriter(), startWorkers(ctx, h, defaultErrorHandler, rw, 0), 0
+		return rw.GetWriter(), startWorkers(ctx, h, rw, o), o.pool
 	}
 }
 
 type ErrorHandler func(context.Context, ErrorWriter, error)
 
-// HandlerOption returns Handler, error Handler and worker pool to use in Pipeline.
-type HandlerOptions func(ErrorHandler, int) (ErrorHandler, int)
+// HandlerOptions receives current stage options and returns updated ones.
+type HandlerOptions func(handlerOptions) handlerOptions
+
+type handlerOptions struct {
+	errorHandler ErrorHandler
+	pool         int
+	buffer       int
+	ordered      bool
+	retry        *RetryPolicy
+	name         string
+	observer     Observer
+	// func(context.Context) error or keyed func(context.Context, T) error
+	limit      any
+	timeout    time.Duration
+	breaker    *Breaker
+	noRecovery bool
+	partition  any
+}
+
+// Returns options for stages nested in the same Pipe call: pool size and ordering.
+func (o handlerOptions) inherited() HandlerOptions {
+	return func(old handlerOptions) handlerOptions {
+		old.pool = o.pool
+		old.ordered = o.ordered
+
+		return old
+	}
+}
+
+// Returns options of the stage with input type T.
+// Panics if option for other input type is set.
+func newStageOptions[T any](opts []HandlerOptions) handlerOptions {
+	o := newHandlerOptions(opts)
+	stageLimit[T](o)
+	partitionKey[T](o)
+
+	return o
+}
+
+func newHandlerOptions(opts []HandlerOptions) handlerOptions {
+	o := handlerOptions{errorHandler: defaultErrorHandler}
+	for _, option := range opts {
+		o = option(o)
+	}
+
+	return o
+}
 
 // Option to use with handler.
 func WithOptions(errorHandler ErrorHandler, handlerPool int) HandlerOptions {
-	return func(oldErrorHandler ErrorHandler, oldHandlerPool int) (ErrorHandler, int) {
-		if errorHandler == nil {
-			errorHandler = oldErrorHandler
+	return func(o handlerOptions) handlerOptions {
+		if errorHandler != nil {
+			o.errorHandler = errorHandler
 		}
 
-		if handlerPool < 1 {
-			handlerPool = oldHandlerPool
+		if handlerPool > 0 {
+			o.pool = handlerPool
 		}
 
-		return errorHandler, handlerPool
+		return o
 	}
 }
 
@@ -54,6 +94,40 @@ func WithErrorHandler(errorHandler ErrorHandler) HandlerOptions {
 	return WithOptions(errorHandler, 0)
 }
 
+// Option that specifies how many events stage output can hold before handler writes block.
+func WithBuffer(size int) HandlerOptions {
+	return func(o handlerOptions) handlerOptions {
+		if size > 0 {
+			o.buffer = size
+		}
+
+		return o
+	}
+}
+
+// Option that makes stage emit results in the order its input events arrived,
+// regardless of handler pool size.
+// Number of input events handled ahead of the one whose results are awaited is bounded by handler pool size,
+// but results handler writes for a single event are collected without limit
+// and its writes never wait for the next stage.
+func WithOrderPreserved() HandlerOptions {
+	return func(o handlerOptions) handlerOptions {
+		o.ordered = true
+
+		return o
+	}
+}
+
+// Option that makes stage handler panics crash the program instead of being written as PanicError.
+// Meant for tests and debugging.
+func WithoutRecovery() HandlerOptions {
+	return func(o handlerOptions) handlerOptions {
+		o.noRecovery = true
+
+		return o
+	}
+}
+
 // Handler that writes same payload it receives without changes.
 func PassThrough[T any]() Handler[T, T] {
 	return func(ctx context.Context, w EventWriter[T], payload T) {
@@ -65,6 +139,13 @@ func PassThrough[T any]() Handler[T, T] {
 func HandleFunc[T, U any](handle Handle[T, U]) Handler[T, U] {
 	return func(ctx context.Context, w EventWriter[U], payload T) {
 		v, err := handle(ctx, payload)
+		// error already carries payload, e.g. returned by Retry
+		if e, ok := err.(*Error[T]); ok {
+			w.WriteError(e)
+
+			return
+		}
+
 		if err != nil {
 			w.WriteError(NewError(err, payload))
 
@@ -79,11 +160,18 @@ var defaultErrorHandler ErrorHandler = func(ctx context.Context, w ErrorWriter,
 	w.WriteError(err)
 }
 
+// Returns handle as Handler of errors stage with output type U receives.
+func handleErrors[U any](handle ErrorHandler) Handler[error, U] {
+	return func(ctx context.Context, w EventWriter[U], err error) {
+		handle(ctx, w, err)
+	}
+}
+
 func errHandleWithRecovery(handle ErrorHandler) ErrorHandler {
 	return func(ctx context.Context, w ErrorWriter, err error) {
 		defer func() {
 			if r := recover(); r != nil {
-				w.WriteError(NewError(fmt.Errorf("recovered from panic: %v", r), err))
+				w.WriteError(NewError(newPanicError(r), err))
 			}
 		}()
 
@@ -95,7 +183,7 @@ func withRecovery[T, U any, H Handler[T, U]](handle H) H {
 	return func(ctx context.Context, w EventWriter[U], payload T) {
 		defer func() {
 			if r := recover(); r != nil {
-				w.WriteError(NewError(fmt.Errorf("recovered from panic: %v", r), payload))
+				w.WriteError(NewError(newPanicError(r), payload))
 			}
 		}()
 
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/andriiyaremenko/pipelines"
 	. "github.com/onsi/ginkgo/v2"
@@ -49,4 +51,74 @@ var _ = Describe("Handler", func() {
 
 		fn(context.TODO(), w, 1)
 	})
+
+	It("Pipeline should use handler pool", func() {
+		var running sync.WaitGroup
+		running.Add(2)
+
+		handler := func(ctx context.Context, w pipelines.EventWriter[bool], _ int) {
+			running.Done()
+
+			ready := make(chan struct{})
+			go func() {
+				running.Wait()
+				close(ready)
+			}()
+
+			select {
+			case <-ready:
+				w.Write(true)
+			case <-time.After(time.Second):
+				w.Write(false)
+			}
+		}
+
+		c := pipelines.Handler[int, bool](handler).Pipeline(pipelines.WithHandlerPool(2))
+
+		payloads := func(yield func(int) bool) {
+			_ = yield(1) && yield(2)
+		}
+
+		count := 0
+		for concurrent, err := range c.HandleSeq(context.TODO(), payloads) {
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(concurrent).Should(BeTrue())
+			count++
+		}
+
+		Expect(count).To(Equal(2))
+
+		w, r, pool := c(context.TODO())
+		w.Close()
+
+		for range r.Read() {
+		}
+
+		Expect(pool).To(Equal(2))
+	})
+
+	It("Pipeline should use error handler", func() {
+		handleErr := func(ctx context.Context, w pipelines.ErrorWriter, err error) {
+			w.WriteError(fmt.Errorf("handled: %w", err))
+		}
+
+		c := pipelines.PassThrough[int]().Pipeline(pipelines.WithErrorHandler(handleErr))
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		w, r, _ := c(ctx)
+		go func() {
+			w.WriteError(errors.New("failed"))
+			w.Close()
+		}()
+
+		errs := []error{}
+		for e := range r.Read() {
+			errs = append(errs, e.Err)
+		}
+
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0]).Should(MatchError("handled: failed"))
+	})
 })
@@ -0,0 +1,181 @@
+package pipelines
+
+import (
+	"context"
+	"time"
+)
+
+// Observer is notified about events going through Pipeline stages.
+// Methods are called concurrently from stage workers and should not block.
+type Observer interface {
+	// Called before stage handles event.
+	// Returned context is passed to the handler and to other methods called for this event.
+	// depth is number of events waiting in stage input buffer.
+	OnEventIn(ctx context.Context, stage string, depth int) context.Context
+	// Called after stage handled event.
+	OnHandled(ctx context.Context, stage string, duration time.Duration)
+	// Called when stage handler writes event or error.
+	OnEventOut(ctx context.Context, stage string)
+	// Called when stage receives error and passes it to its ErrorHandler.
+	OnError(ctx context.Context, stage string, err error)
+	// Called when stage handler panics.
+	OnPanic(ctx context.Context, stage string, recovered any)
+}
+
+// NopObserver ignores all notifications.
+// Embed it to implement only methods of Observer you need.
+type NopObserver struct{}
+
+func (NopObserver) OnEventIn(ctx context.Context, _ string, _ int) context.Context {
+	return ctx
+}
+
+func (NopObserver) OnHandled(context.Context, string, time.Duration) {}
+
+func (NopObserver) OnEventOut(context.Context, string) {}
+
+func (NopObserver) OnError(context.Context, string, error) {}
+
+func (NopObserver) OnPanic(context.Context, string, any) {}
+
+// Option that names stage. Name is passed to Observer and set on Error written by stage.
+func WithName(name string) HandlerOptions {
+	return func(o handlerOptions) handlerOptions {
+		o.name = name
+
+		return o
+	}
+}
+
+// Option that makes stage notify obs about its events.
+// Takes precedence over Observer set with Observe.
+func WithObserver(obs Observer) HandlerOptions {
+	return func(o handlerOptions) handlerOptions {
+		o.observer = obs
+
+		return o
+	}
+}
+
+type observerKey struct{}
+
+// Returns `Pipeline[T, U]` with every stage of p notifying obs about its events,
+// unless stage has its own Observer.
+// Should wrap complete Pipeline: stages added to the result with Pipe are not affected.
+func Observe[T, U any](p Pipeline[T, U], obs Observer) Pipeline[T, U] {
+	return func(ctx context.Context) (EventWriterCloser[T], EventReader[U], int) {
+		return p(context.WithValue(ctx, observerKey{}, obs))
+	}
+}
+
+// Returns Handler that sets stage on every Error handle writes.
+func withStage[T, U any](handle Handler[T, U], stage string) Handler[T, U] {
+	return func(ctx context.Context, w EventWriter[U], payload T) {
+		handle(ctx, &stageWriter[U]{EventWriter: w, stage: stage}, payload)
+	}
+}
+
+type stageWriter[T any] struct {
+	EventWriter[T]
+
+	stage string
+}
+
+func (w *stageWriter[T]) WriteError(err error) {
+	w.EventWriter.WriteError(w.atStage(err))
+}
+
+func (w *stageWriter[T]) writeErrorContext(ctx context.Context, err error) error {
+	return writeErrorContext(ctx, w.EventWriter, w.atStage(err))
+}
+
+func (w *stageWriter[T]) atStage(err error) error {
+	if e, ok := err.(interface{ atStage(string) error }); ok {
+		return e.atStage(w.stage)
+	}
+
+	return err
+}
+
+func (w *stageWriter[T]) TryWrite(e T) error {
+	return asContextWriter(w.EventWriter).TryWrite(e)
+}
+
+func (w *stageWriter[T]) WriteContext(ctx context.Context, e T) error {
+	return asContextWriter(w.EventWriter).WriteContext(ctx, e)
+}
+
+func (o handlerOptions) observerOrFrom(ctx context.Context) Observer {
+	if o.observer != nil {
+		return o.observer
+	}
+
+	obs, _ := ctx.Value(observerKey{}).(Observer)
+
+	return obs
+}
+
+func observe[T, U any](handle Handler[T, U], stage string, obs Observer, depth func() int) Handler[T, U] {
+	return func(ctx context.Context, w EventWriter[U], payload T) {
+		ctx = obs.OnEventIn(ctx, stage, depth())
+		start := time.Now()
+
+		defer func() {
+			if r := recover(); r != nil {
+				obs.OnPanic(ctx, stage, r)
+				obs.OnHandled(ctx, stage, time.Since(start))
+
+				panic(r)
+			}
+
+			obs.OnHandled(ctx, stage, time.Since(start))
+		}()
+
+		handle(ctx, &observedWriter[U]{EventWriter: w, ctx: ctx, stage: stage, obs: obs}, payload)
+	}
+}
+
+func observeErrors[U any](handle Handler[error, U], stage string, obs Observer) Handler[error, U] {
+	return func(ctx context.Context, w EventWriter[U], err error) {
+		obs.OnError(ctx, stage, err)
+		handle(ctx, w, err)
+	}
+}
+
+type observedWriter[T any] struct {
+	EventWriter[T]
+
+	ctx   context.Context
+	stage string
+	obs   Observer
+}
+
+func (w *observedWriter[T]) Write(e T) {
+	w.EventWriter.Write(e)
+	w.obs.OnEventOut(w.ctx, w.stage)
+}
+
+func (w *observedWriter[T]) TryWrite(e T) error {
+	if err := asContextWriter(w.EventWriter).TryWrite(e); err != nil {
+		return err
+	}
+
+	w.obs.OnEventOut(w.ctx, w.stage)
+
+	return nil
+}
+
+func (w *observedWriter[T]) WriteContext(ctx context.Context, e T) error {
+	if err := asContextWriter(w.EventWriter).WriteContext(ctx, e); err != nil {
+		return err
+	}
+
+	w.obs.OnEventOut(w.ctx, w.stage)
+
+	return nil
+}
+
+func (w *observedWriter[T]) WriteError(err error) {
+	w.EventWriter.WriteError(err)
+	w.obs.OnEventOut(w.ctx, w.stage)
+}
@@ -0,0 +1,165 @@
+package pipelines_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type recordingObserver struct {
+	pipelines.NopObserver
+
+	mu       sync.Mutex
+	in       map[string]int
+	out      map[string]int
+	handled  map[string]int
+	errs     map[string]int
+	panics   []any
+	maxDepth int
+}
+
+func newRecordingObserver() *recordingObserver {
+	return &recordingObserver{
+		in:      map[string]int{},
+		out:     map[string]int{},
+		handled: map[string]int{},
+		errs:    map[string]int{},
+	}
+}
+
+func (o *recordingObserver) OnEventIn(ctx context.Context, stage string, depth int) context.Context {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.in[stage]++
+	o.maxDepth = max(o.maxDepth, depth)
+
+	return ctx
+}
+
+func (o *recordingObserver) OnHandled(_ context.Context, stage string, _ time.Duration) {
+	o.mu.Lock()
+	o.handled[stage]++
+	o.mu.Unlock()
+}
+
+func (o *recordingObserver) OnEventOut(_ context.Context, stage string) {
+	o.mu.Lock()
+	o.out[stage]++
+	o.mu.Unlock()
+}
+
+func (o *recordingObserver) OnError(_ context.Context, stage string, _ error) {
+	o.mu.Lock()
+	o.errs[stage]++
+	o.mu.Unlock()
+}
+
+func (o *recordingObserver) OnPanic(_ context.Context, _ string, recovered any) {
+	o.mu.Lock()
+	o.panics = append(o.panics, recovered)
+	o.mu.Unlock()
+}
+
+var _ = Describe("Observer", func() {
+	ctx := context.TODO()
+
+	It("should notify observer about stage events", func() {
+		obs := newRecordingObserver()
+
+		handler := func(ctx context.Context, w pipelines.EventWriter[int], n int) {
+			switch n {
+			case 1:
+				w.WriteError(errors.New("failed"))
+			case 2:
+				panic("oh no...")
+			default:
+				w.Write(n)
+				w.Write(n)
+			}
+		}
+
+		c := pipelines.Observe(
+			pipelines.Pipe(
+				pipelines.Pipe(
+					pipelines.PassThrough[int]().Pipeline(pipelines.WithName("first")),
+					handler,
+					pipelines.WithName("handler"),
+				),
+				pipelines.PassThrough[int](),
+				pipelines.WithName("rest"),
+			),
+			obs,
+		)
+
+		payloads := func(yield func(int) bool) {
+			_ = yield(1) && yield(2) && yield(3)
+		}
+
+		for range c.HandleSeq(ctx, payloads) {
+		}
+
+		Expect(obs.in).To(Equal(map[string]int{"first": 3, "handler": 3, "rest": 2}))
+		Expect(obs.handled).To(Equal(map[string]int{"first": 3, "handler": 3, "rest": 2}))
+		Expect(obs.out).To(Equal(map[string]int{"first": 3, "handler": 3, "rest": 2}))
+		Expect(obs.errs).To(Equal(map[string]int{"rest": 2}))
+		Expect(obs.panics).To(Equal([]any{"oh no..."}))
+	})
+
+	It("should prefer stage observer", func() {
+		pipelineObs := newRecordingObserver()
+		stageObs := newRecordingObserver()
+
+		c := pipelines.Observe(
+			pipelines.Pipe(
+				pipelines.PassThrough[int]().Pipeline(pipelines.WithName("first")),
+				pipelines.PassThrough[int](),
+				pipelines.WithName("second"),
+				pipelines.WithObserver(stageObs),
+			),
+			pipelineObs,
+		)
+
+		for range c.Handle(ctx, 1) {
+		}
+
+		Expect(pipelineObs.in).To(Equal(map[string]int{"first": 1}))
+		Expect(stageObs.in).To(Equal(map[string]int{"second": 1}))
+	})
+
+	It("should report stage queue depth", func() {
+		obs := newRecordingObserver()
+		gate := make(chan struct{})
+
+		slow := func(ctx context.Context, w pipelines.EventWriter[int], n int) {
+			<-gate
+			w.Write(n)
+		}
+
+		c := pipelines.Pipe(
+			pipelines.PassThrough[int]().Pipeline(pipelines.WithBuffer(10)),
+			slow,
+			pipelines.WithObserver(obs),
+		)
+
+		payloads := func(yield func(int) bool) {
+			for i := 0; i < 10; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+
+			close(gate)
+		}
+
+		for range c.HandleSeq(ctx, payloads) {
+		}
+
+		Expect(obs.maxDepth).To(BeNumerically(">", 0))
+	})
+})
@@ -0,0 +1,190 @@
+package pipelines
+
+import (
+	"context"
+	"sync"
+)
+
+// Number of events per worker ordered stage can hold while waiting for an earlier result.
+const orderBufferPerWorker = 2
+
+type orderPreservedKey struct{}
+
+// Returns `Pipeline[T, U]` that keeps every stage of p ordered,
+// so results are emitted in the order initial payloads were written.
+// Should wrap complete Pipeline: stages added to the result with Pipe are not affected.
+func PreserveOrder[T, U any](p Pipeline[T, U]) Pipeline[T, U] {
+	return func(ctx context.Context) (EventWriterCloser[T], EventReader[U], int) {
+		return p(context.WithValue(ctx, orderPreservedKey{}, true))
+	}
+}
+
+func isOrderPreserved(ctx context.Context) bool {
+	ordered, _ := ctx.Value(orderPreservedKey{}).(bool)
+
+	return ordered
+}
+
+type orderedJob[T any] struct {
+	seq   int
+	event *Event[T]
+}
+
+type orderedResult[T any] struct {
+	seq    int
+	events []Event[T]
+}
+
+// eventCollector is an EventWriter that keeps everything written to it.
+// It has no limit, so TryWrite always succeeds.
+type eventCollector[T any] struct {
+	mu     sync.Mutex
+	events []Event[T]
+}
+
+func (c *eventCollector[T]) Write(e T) {
+	c.mu.Lock()
+	c.events = append(c.events, Event[T]{Payload: e})
+	c.mu.Unlock()
+}
+
+func (c *eventCollector[T]) WriteError(err error) {
+	c.mu.Lock()
+	c.events = append(c.events, Event[T]{Err: err})
+	c.mu.Unlock()
+}
+
+func (c *eventCollector[T]) TryWrite(e T) error {
+	c.Write(e)
+
+	return nil
+}
+
+func (c *eventCollector[T]) WriteContext(ctx context.Context, e T) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.Write(e)
+
+	return nil
+}
+
+func (c *eventCollector[T]) writeErrorContext(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	c.WriteError(err)
+
+	return nil
+}
+
+func (c *eventCollector[T]) sendAcked(ctx context.Context, payload T, err error, _ bool, a *ack) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.events = append(c.events, Event[T]{Payload: payload, Err: err, ack: a})
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *eventCollector[T]) collected() []Event[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.events
+}
+
+func startOrderedWorkers[T, U any](
+	ctx context.Context,
+	handle Handler[T, U],
+	errHandle Handler[error, U],
+	r EventReader[T],
+	rw EventReader[U],
+	workers int,
+	key func(T) string,
+) {
+	w := rw.GetWriter()
+
+	slots := make(chan struct{}, workers*orderBufferPerWorker)
+	jobs := make(chan orderedJob[T])
+	results := make(chan orderedResult[U])
+
+	sources := make([]<-chan orderedJob[T], workers)
+	if key != nil {
+		sources = partition(jobs, workers, func(job orderedJob[T]) (string, bool) {
+			if job.event.Err != nil {
+				return "", false
+			}
+
+			return key(job.event.Payload), true
+		})
+	} else {
+		for i := range sources {
+			sources[i] = jobs
+		}
+	}
+
+	go func() {
+		seq := 0
+		for event := range r.Read() {
+			slots <- struct{}{}
+			jobs <- orderedJob[T]{seq: seq, event: event}
+			seq++
+		}
+
+		close(jobs)
+	}()
+
+	var wg sync.WaitGroup
+	for _, source := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for job := range source {
+				c := new(eventCollector[U])
+				a := job.event.ack
+				if job.event.Err != nil {
+					errHandle(ctx, withAck[U](c, a), job.event.Err)
+				} else {
+					handle(ctx, withAck[U](c, a), job.event.Payload)
+					r.Dispose(job.event)
+				}
+
+				a.done(nil)
+
+				results <- orderedResult[U]{seq: job.seq, events: c.collected()}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	go func() {
+		next := 0
+		pending := make(map[int][]Event[U])
+		for result := range results {
+			pending[result.seq] = result.events
+
+			for events, ok := pending[next]; ok; events, ok = pending[next] {
+				for _, e := range events {
+					passOn(w, &e)
+				}
+
+				delete(pending, next)
+				next++
+				<-slots
+			}
+		}
+
+		w.Close()
+	}()
+}
@@ -0,0 +1,109 @@
+package pipelines_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Order", func() {
+	ctx := context.TODO()
+
+	payloads := func(n int) func(yield func(int) bool) {
+		return func(yield func(int) bool) {
+			for i := 0; i < n; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+	}
+
+	sleepy := func(ctx context.Context, w pipelines.EventWriter[int], e int) {
+		time.Sleep(time.Duration(rand.Intn(500)) * time.Microsecond)
+		w.Write(e)
+	}
+
+	expected := func(n int) []int {
+		result := make([]int, n)
+		for i := range result {
+			result[i] = i
+		}
+
+		return result
+	}
+
+	It("should preserve order of stage results with handler pool", func() {
+		writeTwice := func(ctx context.Context, w pipelines.EventWriter[string], e int) {
+			time.Sleep(time.Duration(rand.Intn(500)) * time.Microsecond)
+			w.Write(fmt.Sprintf("%d-a", e))
+			w.Write(fmt.Sprintf("%d-b", e))
+		}
+
+		c := pipelines.Pipe(
+			pipelines.PassThrough[int]().Pipeline(),
+			writeTwice,
+			pipelines.WithHandlerPool(8),
+			pipelines.WithOrderPreserved(),
+		)
+
+		accumulated := []string{}
+		for value, err := range c.HandleSeq(ctx, payloads(5)) {
+			Expect(err).ShouldNot(HaveOccurred())
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(Equal([]string{"0-a", "0-b", "1-a", "1-b", "2-a", "2-b", "3-a", "3-b", "4-a", "4-b"}))
+	})
+
+	It("should preserve order of errors", func() {
+		failOdd := func(ctx context.Context, w pipelines.EventWriter[int], e int) {
+			time.Sleep(time.Duration(rand.Intn(500)) * time.Microsecond)
+			if e%2 == 1 {
+				w.WriteError(pipelines.NewError(fmt.Errorf("odd"), e))
+
+				return
+			}
+
+			w.Write(e)
+		}
+
+		c := pipelines.PreserveOrder(
+			pipelines.Pipe(pipelines.PassThrough[int]().Pipeline(), failOdd, pipelines.WithHandlerPool(4)),
+		)
+
+		accumulated := []int{}
+		for value, err := range c.HandleSeq(ctx, payloads(20)) {
+			if err != nil {
+				value = err.(*pipelines.Error[int]).Payload
+			}
+
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(Equal(expected(20)))
+	})
+
+	It("should preserve order end-to-end", func() {
+		c := pipelines.PreserveOrder(
+			pipelines.Pipe4(
+				pipelines.Handler[int, int](sleepy).Pipeline(),
+				sleepy, sleepy, sleepy, sleepy,
+				pipelines.WithHandlerPool(8),
+			),
+		)
+
+		accumulated := []int{}
+		for value, err := range c.HandleSeq(ctx, payloads(200)) {
+			Expect(err).ShouldNot(HaveOccurred())
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(Equal(expected(200)))
+	})
+})
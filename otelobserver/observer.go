@@ -0,0 +1,135 @@
+// Package otelobserver provides pipelines.Observer that reports stage events
+// as OpenTelemetry spans and metrics.
+package otelobserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andriiyaremenko/pipelines"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/andriiyaremenko/pipelines/otelobserver"
+
+	// Attribute holding stage name.
+	StageKey = attribute.Key("pipelines.stage")
+)
+
+var _ pipelines.Observer = (*Observer)(nil)
+
+// Returns Observer that starts a span for every event handled by stage
+// and records stage metrics using tp and mp.
+func New(tp trace.TracerProvider, mp metric.MeterProvider) (*Observer, error) {
+	meter := mp.Meter(instrumentationName)
+	o := &Observer{tracer: tp.Tracer(instrumentationName)}
+
+	var err error
+	if o.eventsIn, err = meter.Int64Counter(
+		"pipelines.stage.events.in",
+		metric.WithDescription("Number of events received by stage handler."),
+	); err != nil {
+		return nil, err
+	}
+
+	if o.eventsOut, err = meter.Int64Counter(
+		"pipelines.stage.events.out",
+		metric.WithDescription("Number of events and errors written by stage handler."),
+	); err != nil {
+		return nil, err
+	}
+
+	if o.errors, err = meter.Int64Counter(
+		"pipelines.stage.errors",
+		metric.WithDescription("Number of errors passed to stage error handler."),
+	); err != nil {
+		return nil, err
+	}
+
+	if o.panics, err = meter.Int64Counter(
+		"pipelines.stage.panics",
+		metric.WithDescription("Number of stage handler panics."),
+	); err != nil {
+		return nil, err
+	}
+
+	if o.duration, err = meter.Float64Histogram(
+		"pipelines.stage.duration",
+		metric.WithDescription("Duration of stage handler execution."),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, err
+	}
+
+	if o.queueDepth, err = meter.Int64Gauge(
+		"pipelines.stage.queue.depth",
+		metric.WithDescription("Number of events waiting in stage input buffer."),
+	); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// Observer reports stage events as OpenTelemetry spans and metrics.
+type Observer struct {
+	tracer     trace.Tracer
+	eventsIn   metric.Int64Counter
+	eventsOut  metric.Int64Counter
+	errors     metric.Int64Counter
+	panics     metric.Int64Counter
+	duration   metric.Float64Histogram
+	queueDepth metric.Int64Gauge
+}
+
+func (o *Observer) OnEventIn(ctx context.Context, stage string, depth int) context.Context {
+	attrs := metric.WithAttributes(StageKey.String(stage))
+
+	o.eventsIn.Add(ctx, 1, attrs)
+	o.queueDepth.Record(ctx, int64(depth), attrs)
+
+	ctx, _ = o.tracer.Start(ctx, spanName(stage), trace.WithAttributes(StageKey.String(stage)))
+
+	return ctx
+}
+
+func (o *Observer) OnHandled(ctx context.Context, stage string, duration time.Duration) {
+	o.duration.Record(ctx, duration.Seconds(), metric.WithAttributes(StageKey.String(stage)))
+
+	trace.SpanFromContext(ctx).End()
+}
+
+func (o *Observer) OnEventOut(ctx context.Context, stage string) {
+	o.eventsOut.Add(ctx, 1, metric.WithAttributes(StageKey.String(stage)))
+}
+
+// Errors are passed to error handler outside of event span, so every error is recorded on its own span.
+func (o *Observer) OnError(ctx context.Context, stage string, err error) {
+	o.errors.Add(ctx, 1, metric.WithAttributes(StageKey.String(stage)))
+
+	_, span := o.tracer.Start(ctx, spanName(stage), trace.WithAttributes(StageKey.String(stage)))
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	span.End()
+}
+
+func (o *Observer) OnPanic(ctx context.Context, stage string, recovered any) {
+	o.panics.Add(ctx, 1, metric.WithAttributes(StageKey.String(stage)))
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(fmt.Errorf("panic: %v", recovered))
+	span.SetStatus(codes.Error, "handler panicked")
+}
+
+func spanName(stage string) string {
+	if stage == "" {
+		return "pipelines.stage"
+	}
+
+	return "pipelines.stage " + stage
+}
@@ -0,0 +1,171 @@
+package otelobserver_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/andriiyaremenko/pipelines"
+	"github.com/andriiyaremenko/pipelines/otelobserver"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var _ = Describe("Observer", func() {
+	ctx := context.TODO()
+
+	It("should report stage spans and metrics", func() {
+		exporter := tracetest.NewInMemoryExporter()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		reader := sdkmetric.NewManualReader()
+		mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+		obs, err := otelobserver.New(tp, mp)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		handler := func(ctx context.Context, w pipelines.EventWriter[int], n int) {
+			if n == 3 {
+				panic("three")
+			}
+
+			w.Write(n)
+		}
+
+		c := pipelines.Observe(
+			pipelines.Pipe(
+				pipelines.PassThrough[int]().Pipeline(pipelines.WithName("source")),
+				handler,
+				pipelines.WithName("sink"),
+			),
+			obs,
+		)
+
+		payloads := func(yield func(int) bool) {
+			for i := 1; i <= 4; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+
+		for range c.HandleSeq(ctx, payloads) {
+		}
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(8))
+
+		names := map[string]int{}
+		panicked := 0
+		for _, span := range spans {
+			names[span.Name]++
+			if span.Status.Code == codes.Error {
+				panicked++
+			}
+		}
+
+		Expect(names).To(Equal(map[string]int{"pipelines.stage source": 4, "pipelines.stage sink": 4}))
+		Expect(panicked).To(Equal(1))
+
+		var rm metricdata.ResourceMetrics
+		Expect(reader.Collect(ctx, &rm)).Should(Succeed())
+
+		sums := map[string]map[string]int64{}
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				data, ok := m.Data.(metricdata.Sum[int64])
+				if !ok {
+					continue
+				}
+
+				sums[m.Name] = map[string]int64{}
+				for _, point := range data.DataPoints {
+					stage, _ := point.Attributes.Value(otelobserver.StageKey)
+					sums[m.Name][stage.AsString()] = point.Value
+				}
+			}
+		}
+
+		Expect(sums["pipelines.stage.events.in"]).To(Equal(map[string]int64{"source": 4, "sink": 4}))
+		Expect(sums["pipelines.stage.events.out"]).To(Equal(map[string]int64{"source": 4, "sink": 3}))
+		Expect(sums["pipelines.stage.panics"]).To(Equal(map[string]int64{"sink": 1}))
+	})
+
+	It("should count errors passed to error handler", func() {
+		reader := sdkmetric.NewManualReader()
+		obs, err := otelobserver.New(
+			sdktrace.NewTracerProvider(),
+			sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		failing := pipelines.HandleFunc(
+			pipelines.LiftErr(func(context.Context, int) error { return errors.New("failed") }),
+		)
+		c := pipelines.Pipe(
+			failing.Pipeline(),
+			pipelines.PassThrough[int](),
+			pipelines.WithName("next"),
+			pipelines.WithObserver(obs),
+		)
+
+		for range c.Handle(ctx, 1) {
+		}
+
+		var rm metricdata.ResourceMetrics
+		Expect(reader.Collect(ctx, &rm)).Should(Succeed())
+
+		found := false
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name == "pipelines.stage.errors" {
+					found = true
+					Expect(m.Data.(metricdata.Sum[int64]).DataPoints[0].Value).To(Equal(int64(1)))
+				}
+			}
+		}
+
+		Expect(found).Should(BeTrue())
+	})
+
+	It("should record errors on their own spans", func() {
+		exporter := tracetest.NewInMemoryExporter()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		obs, err := otelobserver.New(tp, sdkmetric.NewMeterProvider())
+		Expect(err).ShouldNot(HaveOccurred())
+
+		failing := pipelines.HandleFunc(
+			pipelines.LiftErr(func(context.Context, int) error { return errors.New("failed") }),
+		)
+		c := pipelines.Pipe(
+			failing.Pipeline(),
+			pipelines.PassThrough[int](),
+			pipelines.WithName("next"),
+			pipelines.WithObserver(obs),
+		)
+
+		ctx, parent := tp.Tracer("test").Start(ctx, "caller")
+		for range c.Handle(ctx, 1) {
+		}
+		parent.End()
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(2))
+
+		for _, span := range spans {
+			if span.Name == "caller" {
+				Expect(span.Events).To(BeEmpty())
+
+				continue
+			}
+
+			Expect(span.Name).To(Equal("pipelines.stage next"))
+			Expect(span.Parent.SpanID()).To(Equal(parent.SpanContext().SpanID()))
+			Expect(span.Status.Code).To(Equal(codes.Error))
+			Expect(span.Events).To(HaveLen(1))
+		}
+	})
+})
@@ -0,0 +1,13 @@
+package otelobserver_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOtelObserver(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "OtelObserver Suite")
+}
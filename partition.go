@@ -0,0 +1,67 @@
+package pipelines
+
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+)
+
+// Option that makes stage handle events with the same key on the same worker,
+// so they are handled one at a time in the order they arrived,
+// while events with different keys are handled in parallel.
+// Stage input type should be T, otherwise stage panics on construction.
+func WithPartitionKey[T any](key func(T) string) HandlerOptions {
+	return func(o handlerOptions) handlerOptions {
+		o.partition = key
+
+		return o
+	}
+}
+
+// Returns partition key of the stage with input type T or nil.
+// Panics if partition key is set for other input type.
+func partitionKey[T any](o handlerOptions) func(T) string {
+	if o.partition == nil {
+		return nil
+	}
+
+	key, ok := o.partition.(func(T) string)
+	if !ok {
+		panic(fmt.Sprintf("pipelines: partition key %T does not match stage input type %v", o.partition, reflect.TypeFor[T]()))
+	}
+
+	return key
+}
+
+// Splits in into channel per worker.
+// Items key returns true for go to the channel chosen by their key hash, other items are spread evenly.
+func partition[E any](in <-chan E, workers int, key func(E) (string, bool)) []<-chan E {
+	outs := make([]chan E, workers)
+	result := make([]<-chan E, workers)
+	for i := range outs {
+		outs[i] = make(chan E)
+		result[i] = outs[i]
+	}
+
+	go func() {
+		next := 0
+		for item := range in {
+			i := next
+			if k, ok := key(item); ok {
+				h := fnv.New32a()
+				_, _ = h.Write([]byte(k))
+				i = int(h.Sum32() % uint32(workers))
+			} else {
+				next = (next + 1) % workers
+			}
+
+			outs[i] <- item
+		}
+
+		for _, out := range outs {
+			close(out)
+		}
+	}()
+
+	return result
+}
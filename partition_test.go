@@ -0,0 +1,129 @@
+package pipelines_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type account struct {
+	id  string
+	seq int
+}
+
+var _ = Describe("Partition", func() {
+	ctx := context.TODO()
+
+	accounts := func(ids, n int) func(yield func(account) bool) {
+		return func(yield func(account) bool) {
+			for seq := 0; seq < n; seq++ {
+				for id := 0; id < ids; id++ {
+					if !yield(account{id: fmt.Sprintf("account-%d", id), seq: seq}) {
+						return
+					}
+				}
+			}
+		}
+	}
+
+	run := func(opts ...pipelines.HandlerOptions) (map[string][]int, int64) {
+		var (
+			mu      sync.Mutex
+			handled = make(map[string][]int)
+			active  sync.Map
+			overlap atomic.Int64
+		)
+
+		handler := func(ctx context.Context, w pipelines.EventWriter[account], e account) {
+			if _, busy := active.LoadOrStore(e.id, true); busy {
+				overlap.Add(1)
+			}
+
+			time.Sleep(time.Duration(rand.Intn(300)) * time.Microsecond)
+
+			mu.Lock()
+			handled[e.id] = append(handled[e.id], e.seq)
+			mu.Unlock()
+
+			active.Delete(e.id)
+			w.Write(e)
+		}
+
+		opts = append(opts, pipelines.WithPartitionKey(func(e account) string { return e.id }))
+		c := pipelines.Pipe(
+			pipelines.PassThrough[account]().Pipeline(),
+			handler,
+			opts...,
+		)
+
+		for _, err := range c.HandleSeq(ctx, accounts(5, 20)) {
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		return handled, overlap.Load()
+	}
+
+	expected := func() []int {
+		result := make([]int, 20)
+		for i := range result {
+			result[i] = i
+		}
+
+		return result
+	}
+
+	It("should handle events with the same key sequentially and in order", func() {
+		handled, overlap := run(pipelines.WithHandlerPool(4))
+
+		Expect(overlap).To(BeZero())
+		Expect(handled).To(HaveLen(5))
+		for _, seqs := range handled {
+			Expect(seqs).To(Equal(expected()))
+		}
+	})
+
+	It("should partition ordered stage", func() {
+		handled, overlap := run(pipelines.WithHandlerPool(4), pipelines.WithOrderPreserved())
+
+		Expect(overlap).To(BeZero())
+		for _, seqs := range handled {
+			Expect(seqs).To(Equal(expected()))
+		}
+	})
+
+	It("should reject key of other input type", func() {
+		key := pipelines.WithPartitionKey(func(e account) string { return e.id })
+
+		Expect(func() {
+			pipelines.Pipe(pipelines.PassThrough[int]().Pipeline(), pipelines.PassThrough[int](), key)
+		}).To(Panic())
+		Expect(func() {
+			pipelines.NewStage(pipelines.PassThrough[int](), key)
+		}).To(Panic())
+	})
+
+	It("should apply key to the last stage of Pipe2", func() {
+		c := pipelines.Pipe2(
+			pipelines.PassThrough[int]().Pipeline(),
+			pipelines.Map(func(e int) account { return account{id: "a", seq: e} }),
+			pipelines.PassThrough[account](),
+			pipelines.WithPartitionKey(func(e account) string { return e.id }),
+			pipelines.WithHandlerPool(2),
+		)
+
+		count := 0
+		for _, err := range c.Handle(ctx, 1) {
+			Expect(err).ShouldNot(HaveOccurred())
+			count++
+		}
+
+		Expect(count).To(Equal(1))
+	})
+})
@@ -2,93 +2,72 @@ package pipelines
 
 import (
 	"context"
+	"errors"
 	"iter"
+	"time"
 )
 
 // Adds next `Handler[U, H]` to the `Pipeline[T, U]` resulting in new `Pipeline[T, H]`.
 func Pipe[T, U, N any, P Pipeline[T, U]](p P, h Handler[U, N], opts ...HandlerOptions) Pipeline[T, N] {
-	h = withRecovery(h)
-	errHandler := defaultErrorHandler
-	pool := 0
-
-	for _, option := range opts {
-		errHandler, pool = option(errHandler, pool)
-	}
+	o := newStageOptions[U](opts)
 
 	return func(ctx context.Context) (EventWriterCloser[T], EventReader[N], int) {
 		w, r, oldPool := p(ctx)
-		if pool < oldPool {
-			pool = oldPool
+		o := o
+		if o.pool < oldPool {
+			o.pool = oldPool
 		}
 
-		return w, startWorkers(ctx, h, errHandler, r, pool), pool
+		return w, startWorkers(ctx, h, r, o), o.pool
 	}
 }
 
 func Pipe2[T, U, N, S any, P Pipeline[T, U]](p P, h1 Handler[U, N], h2 Handler[N, S], opts ...HandlerOptions) Pipeline[T, S] {
-	errHandler := defaultErrorHandler
-	pool := 0
-
-	for _, option := range opts {
-		errHandler, pool = option(errHandler, pool)
-	}
-
-	_p := Pipe(p, h1, WithHandlerPool(pool))
-	h2 = withRecovery(h2)
+	o := newStageOptions[N](opts)
+	_p := Pipe(p, h1, o.inherited())
 
 	return func(ctx context.Context) (EventWriterCloser[T], EventReader[S], int) {
 		w, r, oldPool := _p(ctx)
-		if pool < oldPool {
-			pool = oldPool
+		o := o
+		if o.pool < oldPool {
+			o.pool = oldPool
 		}
 
-		return w, startWorkers(ctx, h2, errHandler, r, pool), pool
+		return w, startWorkers(ctx, h2, r, o), o.pool
 	}
 }
 
 func Pipe3[T, U, N, S, Y any, P Pipeline[T, U]](
 	p P, h1 Handler[U, N], h2 Handler[N, S], h3 Handler[S, Y], opts ...HandlerOptions,
 ) Pipeline[T, Y] {
-	errHandler := defaultErrorHandler
-	pool := 0
-
-	for _, option := range opts {
-		errHandler, pool = option(errHandler, pool)
-	}
-
-	_p := Pipe2(p, h1, h2, WithHandlerPool(pool))
-	h3 = withRecovery(h3)
+	o := newStageOptions[S](opts)
+	_p := Pipe2(p, h1, h2, o.inherited())
 
 	return func(ctx context.Context) (EventWriterCloser[T], EventReader[Y], int) {
 		w, r, oldPool := _p(ctx)
-		if pool < oldPool {
-			pool = oldPool
+		o := o
+		if o.pool < oldPool {
+			o.pool = oldPool
 		}
 
-		return w, startWorkers(ctx, h3, errHandler, r, pool), pool
+		return w, startWorkers(ctx, h3, r, o), o.pool
 	}
 }
 
 func Pipe4[T, U, N, S, Y, X any, P Pipeline[T, U]](
 	p P, h1 Handler[U, N], h2 Handler[N, S], h3 Handler[S, Y], h4 Handler[Y, X], opts ...HandlerOptions,
 ) Pipeline[T, X] {
-	errHandler := defaultErrorHandler
-	pool := 0
-
-	for _, option := range opts {
-		errHandler, pool = option(errHandler, pool)
-	}
-
-	_p := Pipe3(p, h1, h2, h3, WithHandlerPool(pool))
-	h4 = withRecovery(h4)
+	o := newStageOptions[Y](opts)
+	_p := Pipe3(p, h1, h2, h3, o.inherited())
 
 	return func(ctx context.Context) (EventWriterCloser[T], EventReader[X], int) {
 		w, r, oldPool := _p(ctx)
-		if pool < oldPool {
-			pool = oldPool
+		o := o
+		if o.pool < oldPool {
+			o.pool = oldPool
 		}
 
-		return w, startWorkers(ctx, h4, errHandler, r, pool), pool
+		return w, startWorkers(ctx, h4, r, o), o.pool
 	}
 }
 
@@ -99,18 +78,116 @@ func PipeErrorHandler[T, U any](p Pipeline[T, U], h ErrorHandler) Pipeline[T, U]
 	return func(ctx context.Context) (EventWriterCloser[T], EventReader[U], int) {
 		w, r, pool := p(ctx)
 
-		return w, startWorkers(ctx, PassThrough[U](), h, r, pool), pool
+		return w, startWorkers(ctx, PassThrough[U](), r, handlerOptions{errorHandler: h, pool: pool}), pool
+	}
+}
+
+// Adds recovery to the `Pipeline[T, U]` resulting in new `Pipeline[T, U]`.
+// Every error of p is passed to recoverErr: value it returns with true is written as successful event,
+// otherwise error is dropped.
+func PipeRecover[T, U any](p Pipeline[T, U], recoverErr func(context.Context, error) (U, bool)) Pipeline[T, U] {
+	var h Handler[error, U] = func(ctx context.Context, w EventWriter[U], err error) {
+		if v, ok := recoverErr(ctx, err); ok {
+			w.Write(v)
+		}
+	}
+	h = withRecovery(h)
+
+	return func(ctx context.Context) (EventWriterCloser[T], EventReader[U], int) {
+		w, r, pool := p(ctx)
+
+		return w, startStage(ctx, PassThrough[U](), h, r, handlerOptions{pool: pool}), pool
 	}
 }
 
 // Combination of Handlers into one Pipeline.
 type Pipeline[T, U any] func(context.Context) (EventWriterCloser[T], EventReader[U], int)
 
+// HandleOptions receives current Pipeline execution options and returns updated ones.
+type HandleOptions func(handleOptions) handleOptions
+
+type handleOptions struct {
+	deadline time.Time
+	timeout  time.Duration
+}
+
+func newHandleOptions(opts []HandleOptions) handleOptions {
+	var o handleOptions
+	for _, option := range opts {
+		o = option(o)
+	}
+
+	return o
+}
+
+// Returns deadline of execution starting now, zero if there is none.
+func (o handleOptions) executionDeadline() time.Time {
+	deadline := o.deadline
+	if o.timeout > 0 {
+		if d := time.Now().Add(o.timeout); deadline.IsZero() || d.Before(deadline) {
+			deadline = d
+		}
+	}
+
+	return deadline
+}
+
 // Handles initial Event and returns result of Pipeline execution.
-func (pipeline Pipeline[T, U]) Handle(ctx context.Context, payload T) iter.Seq2[U, error] {
+func (pipeline Pipeline[T, U]) Handle(ctx context.Context, payload T, opts ...HandleOptions) iter.Seq2[U, error] {
+	return pipeline.handle(ctx, opts, func(_ context.Context, w EventWriter[T]) {
+		w.Write(payload)
+	})
+}
+
+// Handles every payload of seq within single Pipeline execution and returns result of it.
+// Pipeline input is closed once seq is exhausted.
+// seq should not block indefinitely: stopping iteration waits for seq to yield its next payload.
+func (pipeline Pipeline[T, U]) HandleSeq(ctx context.Context, seq iter.Seq[T], opts ...HandleOptions) iter.Seq2[U, error] {
+	return pipeline.handle(ctx, opts, func(ctx context.Context, w EventWriter[T]) {
+		for payload := range seq {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			w.Write(payload)
+		}
+	})
+}
+
+// Handles every payload received from payloads within single Pipeline execution and returns result of it.
+// Pipeline input is closed once payloads channel is closed or ctx is done.
+func (pipeline Pipeline[T, U]) HandleChan(ctx context.Context, payloads <-chan T, opts ...HandleOptions) iter.Seq2[U, error] {
+	return pipeline.handle(ctx, opts, func(ctx context.Context, w EventWriter[T]) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case payload, ok := <-payloads:
+				if !ok {
+					return
+				}
+
+				w.Write(payload)
+			}
+		}
+	})
+}
+
+func (pipeline Pipeline[T, U]) handle(
+	ctx context.Context,
+	opts []HandleOptions,
+	feed func(context.Context, EventWriter[T]),
+) iter.Seq2[U, error] {
+	o := newHandleOptions(opts)
+
 	return func(yield func(U, error) bool) {
-		ctx, cancel := context.WithCancel(ctx)
+		parent := ctx
+		ctx, cancel := withDeadline(parent, o.executionDeadline())
+
 		w, r, _ := pipeline(ctx)
+		fed := make(chan struct{})
 
 		defer func() {
 			cancel()
@@ -118,17 +195,36 @@ func (pipeline Pipeline[T, U]) Handle(ctx context.Context, payload T) iter.Seq2[
 			// To avoid stacked goroutines we need to exhaust EventReader.Read() channel.
 			// Cancelling ctx will stop next writes, but due to it being executed in different goroutines
 			// might result into couple dropped events
-			for range r.Read() {
+			for e := range r.Read() {
+				e.ack.done(context.Canceled)
 			}
+
+			// results might end before input does, e.g. with Take
+			<-fed
 		}()
 
-		w.Write(payload)
-		w.Close()
+		go func() {
+			defer close(fed)
+
+			feed(ctx, w)
+			w.Close()
+		}()
 
 		for e := range r.Read() {
+			a := e.ack
 			if !yield(e.Payload, e.Err) {
+				// consumer did not finish handling the result
+				a.done(context.Canceled)
+
 				return
 			}
+
+			a.done(e.Err)
+		}
+
+		// execution was cut by its own deadline, not by the caller
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && parent.Err() == nil {
+			yield(zero[U](), ErrPipelineTimeout)
 		}
 	}
 }
@@ -136,28 +232,102 @@ func (pipeline Pipeline[T, U]) Handle(ctx context.Context, payload T) iter.Seq2[
 func startWorkers[T, U any](
 	ctx context.Context,
 	handle Handler[T, U],
-	errHandle ErrorHandler,
 	r EventReader[T],
-	workers int,
+	o handlerOptions,
 ) EventReader[U] {
-	rw := newEventRW[U](ctx)
+	return startStage(ctx, handle, handleErrors[U](o.errorHandler), r, o)
+}
 
+// Starts stage that passes errors it receives to errHandle instead of error handler of o.
+func startStage[T, U any](
+	ctx context.Context,
+	handle Handler[T, U],
+	errHandle Handler[error, U],
+	r EventReader[T],
+	o handlerOptions,
+) EventReader[U] {
+	workers := o.pool
 	if workers == 0 {
 		workers = 1
 	}
 
-	for i := 0; i < workers; i++ {
-		w := rw.GetWriter()
+	if obs := o.observerOrFrom(ctx); obs != nil {
+		handle = observe(handle, o.name, obs, func() int { return len(r.Read()) })
+		errHandle = observeErrors(errHandle, o.name, obs)
+	}
+
+	if !o.noRecovery {
+		handle = withRecovery(handle)
+	}
+
+	if o.timeout > 0 {
+		handle = withTimeout(handle, o.timeout)
+	}
+
+	if o.breaker != nil {
+		handle = withCircuitBreaker(handle, o.breaker)
+	}
+
+	if o.retry != nil {
+		handle = withRetry(handle, *o.retry)
+	}
+
+	if limit := stageLimit[T](o); limit != nil {
+		handle = withRateLimit(handle, limit)
+	}
+
+	// recovers panics of wrappers too, handler panics are recovered within its own goroutine with timeout
+	if !o.noRecovery {
+		handle = withRecovery(handle)
+	}
+
+	if o.name != "" {
+		handle = withStage(handle, o.name)
+	}
+
+	key := partitionKey[T](o)
+
+	rw := newEventRW[U](ctx, o.buffer)
+	if o.ordered || isOrderPreserved(ctx) {
+		startOrderedWorkers(ctx, handle, errHandle, r, rw, workers, key)
+
+		return rw
+	}
+
+	sources := make([]<-chan *Event[T], workers)
+	if key != nil {
+		sources = partition(r.Read(), workers, func(e *Event[T]) (string, bool) {
+			if e.Err != nil {
+				return "", false
+			}
+
+			return key(e.Payload), true
+		})
+	} else {
+		for i := range sources {
+			sources[i] = r.Read()
+		}
+	}
+
+	writers := make([]EventWriterCloser[U], workers)
+	for i := range writers {
+		writers[i] = rw.GetWriter()
+	}
+
+	for i, w := range writers {
 		go func() {
-			for event := range r.Read() {
+			for event := range sources[i] {
+				a := event.ack
 				if event.Err != nil {
-					errHandle(ctx, w, event.Err)
+					errHandle(ctx, withAck[U](w, a), event.Err)
+					a.done(nil)
 
 					continue
 				}
 
-				handle(ctx, w, event.Payload)
+				handle(ctx, withAck[U](w, a), event.Payload)
 				r.Dispose(event)
+				a.done(nil)
 			}
 
 			w.Close()
@@ -2,7 +2,10 @@ package pipelines_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"sync"
 	"time"
 
@@ -204,6 +207,24 @@ var _ = Describe("Pipeline", func() {
 		Expect(accumulated).To(Equal([]int{3, 3, 3}))
 	})
 
+	It("should expose recovered value and stack as PanicError", func() {
+		cause := fmt.Errorf("boom")
+		explode := func(ctx context.Context, w pipelines.EventWriter[int], e int) {
+			panic(cause)
+		}
+
+		c := pipelines.Pipe(pipelines.PassThrough[int]().Pipeline(), explode)
+
+		for _, err := range c.Handle(ctx, 1) {
+			var panicErr *pipelines.PanicError
+			Expect(errors.As(err, &panicErr)).To(BeTrue())
+			Expect(panicErr.Value).To(Equal(cause))
+			Expect(string(panicErr.Stack)).To(ContainSubstring("pipeline_test.go"))
+			Expect(err).To(MatchError(cause))
+			Expect(err.(*pipelines.Error[int]).Payload).To(Equal(1))
+		}
+	})
+
 	It("should not leak gourutines", func() {
 		handler1 := func(ctx context.Context, r pipelines.EventWriter[int], e string) {
 			r.Write(1)
@@ -316,4 +337,148 @@ var _ = Describe("Pipeline", func() {
 
 		Expect(accumulated).To(Equal([]int{1, 1, 1, 1}))
 	})
+
+	It("should recover errors into values", func() {
+		parse := func(ctx context.Context, s string) (int, error) {
+			return strconv.Atoi(s)
+		}
+		fallback := func(ctx context.Context, err error) (int, bool) {
+			payload, _ := pipelines.PayloadOf[string](err)
+			if payload == "skip" {
+				return 0, false
+			}
+
+			return -1, true
+		}
+
+		c := pipelines.PipeRecover(
+			pipelines.Pipe(pipelines.PassThrough[string]().Pipeline(), pipelines.HandleFunc(parse)),
+			fallback,
+		)
+
+		accumulated := []int{}
+		for value, err := range c.HandleSeq(ctx, slices.Values([]string{"1", "oops", "skip", "2"})) {
+			Expect(err).ShouldNot(HaveOccurred())
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(ConsistOf(1, -1, 2))
+	})
+
+	It("can handle sequence of payloads within single execution", func() {
+		instantiated := 0
+		handler := func(ctx context.Context, r pipelines.EventWriter[int], e int) {
+			r.Write(e * 2)
+		}
+		p := pipelines.Handler[int, int](handler).Pipeline()
+		c := pipelines.Pipeline[int, int](func(ctx context.Context) (pipelines.EventWriterCloser[int], pipelines.EventReader[int], int) {
+			instantiated++
+
+			return p(ctx)
+		})
+
+		payloads := func(yield func(int) bool) {
+			for i := 1; i <= 100; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+
+		sum := 0
+		count := 0
+		for value, err := range c.HandleSeq(ctx, payloads) {
+			Expect(err).ShouldNot(HaveOccurred())
+
+			sum += value
+			count++
+		}
+
+		Expect(count).To(Equal(100))
+		Expect(sum).To(Equal(10100))
+		Expect(instantiated).To(Equal(1))
+	})
+
+	It("can handle payloads from channel", func() {
+		c := pipelines.Pipe(
+			pipelines.HandleFunc(pipelines.LiftOk(func(_ context.Context, n int) int { return n + 1 })).Pipeline(),
+			pipelines.HandleFunc(pipelines.LiftOk(func(_ context.Context, n int) string { return fmt.Sprint(n) })),
+		)
+
+		payloads := make(chan int)
+		go func() {
+			for i := 0; i < 10; i++ {
+				payloads <- i
+			}
+
+			close(payloads)
+		}()
+
+		accumulated := []string{}
+		for value, err := range c.HandleChan(ctx, payloads) {
+			Expect(err).ShouldNot(HaveOccurred())
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(ConsistOf("1", "2", "3", "4", "5", "6", "7", "8", "9", "10"))
+	})
+
+	It("should stop handling channel when ctx is done", func() {
+		ctx, cancel := context.WithCancel(ctx)
+		c := pipelines.PassThrough[int]().Pipeline()
+
+		payloads := make(chan int)
+		go func() {
+			payloads <- 1
+			cancel()
+		}()
+
+		for value, err := range c.HandleChan(ctx, payloads) {
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).To(Equal(1))
+		}
+	})
+
+	It("should not leak goroutines when sequence handling is stopped", func() {
+		payloads := func(yield func(int) bool) {
+			for i := 0; ; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+
+		c := pipelines.Pipe(pipelines.PassThrough[int]().Pipeline(), pipelines.PassThrough[int](), pipelines.WithHandlerPool(4))
+
+		count := 0
+		for _, err := range c.HandleSeq(ctx, payloads) {
+			Expect(err).ShouldNot(HaveOccurred())
+
+			count++
+			if count == 50 {
+				break
+			}
+		}
+
+		Expect(count).To(Equal(50))
+
+		time.Sleep(time.Millisecond * 250)
+
+		err := goleak.Find(
+			goleak.
+				IgnoreTopFunction(
+					"github.com/onsi/ginkgo/v2/internal.(*Suite).runNode",
+				),
+			goleak.
+				IgnoreTopFunction(
+					"github.com/onsi/ginkgo/v2/internal/interrupt_handler.(*InterruptHandler).registerForInterrupts.func2",
+				),
+			goleak.
+				IgnoreAnyFunction(
+					"github.com/onsi/ginkgo/v2/internal.RegisterForProgressSignal.func1",
+				),
+		)
+
+		Expect(err).ShouldNot(HaveOccurred())
+	})
 })
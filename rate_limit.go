@@ -0,0 +1,190 @@
+package pipelines
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Returns Limiter that allows rate events per second on average with bursts of up to burst events.
+func NewLimiter(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &Limiter{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// Limiter is a token bucket. It is safe for concurrent use.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// Waits until the next event is allowed.
+// Returns ctx error if ctx is done first.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release()
+
+		return ctx.Err()
+	}
+}
+
+// Takes a token and returns how long to wait until it is available.
+func (l *Limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+
+	l.last = now
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	if l.rate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// Returns token that was reserved, but not used.
+func (l *Limiter) release() {
+	l.mu.Lock()
+	l.tokens = min(l.burst, l.tokens+1)
+	l.mu.Unlock()
+}
+
+// keyedLimiter keeps separate Limiter for every key.
+type keyedLimiter struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    int
+	limiters map[string]*Limiter
+}
+
+func newKeyedLimiter(rate float64, burst int) *keyedLimiter {
+	return &keyedLimiter{rate: rate, burst: burst, limiters: make(map[string]*Limiter)}
+}
+
+func (l *keyedLimiter) Wait(ctx context.Context, key string) error {
+	l.mu.Lock()
+	limiter, ok := l.limiters[key]
+	if !ok {
+		limiter = NewLimiter(l.rate, l.burst)
+		l.limiters[key] = limiter
+	}
+	l.mu.Unlock()
+
+	return limiter.Wait(ctx)
+}
+
+// Handler that passes events on no faster than rate events per second with bursts of up to burst events.
+// Limit is shared by every Pipeline execution the Handler is used in.
+func RateLimit[T any](rate float64, burst int) Handler[T, T] {
+	return withRateLimit(PassThrough[T](), limitAll[T](NewLimiter(rate, burst)))
+}
+
+// Handler that passes events on no faster than rate events per second with bursts of up to burst events
+// for every key derived from event payload.
+// Limits are shared by every Pipeline execution the Handler is used in.
+// Limiter is kept for every key ever seen, so number of keys should be bounded.
+func KeyedRateLimit[T any](rate float64, burst int, key func(T) string) Handler[T, T] {
+	return withRateLimit(PassThrough[T](), limitByKey(newKeyedLimiter(rate, burst), key))
+}
+
+// Option that makes stage handle no more than rate events per second with bursts of up to burst events.
+// Limit is shared by every Pipeline execution the stage is used in.
+func WithRateLimit(rate float64, burst int) HandlerOptions {
+	limit := NewLimiter(rate, burst).Wait
+
+	return func(o handlerOptions) handlerOptions {
+		o.limit = limit
+
+		return o
+	}
+}
+
+// Option that makes stage handle no more than rate events per second with bursts of up to burst events
+// for every key derived from event payload.
+// Limits are shared by every Pipeline execution the stage is used in.
+// Limiter is kept for every key ever seen, so number of keys should be bounded.
+// Stage input type should be T, otherwise stage panics on construction.
+func WithKeyedRateLimit[T any](rate float64, burst int, key func(T) string) HandlerOptions {
+	limit := limitByKey(newKeyedLimiter(rate, burst), key)
+
+	return func(o handlerOptions) handlerOptions {
+		o.limit = limit
+
+		return o
+	}
+}
+
+func limitAll[T any](limiter *Limiter) func(context.Context, T) error {
+	return func(ctx context.Context, _ T) error {
+		return limiter.Wait(ctx)
+	}
+}
+
+func limitByKey[T any](limiter *keyedLimiter, key func(T) string) func(context.Context, T) error {
+	return func(ctx context.Context, payload T) error {
+		return limiter.Wait(ctx, key(payload))
+	}
+}
+
+// Returns rate limit of the stage with input type T or nil.
+// Panics if keyed rate limit is set for other input type.
+func stageLimit[T any](o handlerOptions) func(context.Context, T) error {
+	switch limit := o.limit.(type) {
+	case nil:
+		return nil
+	case func(context.Context) error:
+		return func(ctx context.Context, _ T) error {
+			return limit(ctx)
+		}
+	case func(context.Context, T) error:
+		return limit
+	default:
+		panic(fmt.Sprintf("pipelines: keyed rate limit %T does not match stage input type %v", limit, reflect.TypeFor[T]()))
+	}
+}
+
+func withRateLimit[T, U any](handle Handler[T, U], limit func(context.Context, T) error) Handler[T, U] {
+	return func(ctx context.Context, w EventWriter[U], payload T) {
+		if err := limit(ctx, payload); err != nil {
+			w.WriteError(NewError(err, payload))
+
+			return
+		}
+
+		handle(ctx, w, payload)
+	}
+}
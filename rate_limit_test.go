@@ -0,0 +1,173 @@
+package pipelines_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RateLimit", func() {
+	ctx := context.TODO()
+
+	payloads := func(n int) func(yield func(int) bool) {
+		return func(yield func(int) bool) {
+			for i := 0; i < n; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+	}
+
+	It("should allow burst and then limit rate", func() {
+		l := pipelines.NewLimiter(100, 5)
+
+		start := time.Now()
+		for i := 0; i < 5; i++ {
+			Expect(l.Wait(ctx)).Should(Succeed())
+		}
+
+		Expect(time.Since(start)).To(BeNumerically("<", time.Millisecond*10))
+
+		for i := 0; i < 5; i++ {
+			Expect(l.Wait(ctx)).Should(Succeed())
+		}
+
+		Expect(time.Since(start)).To(BeNumerically(">=", time.Millisecond*40))
+	})
+
+	It("should stop waiting when ctx is done", func() {
+		l := pipelines.NewLimiter(0.1, 1)
+		Expect(l.Wait(ctx)).Should(Succeed())
+
+		ctx, cancel := context.WithTimeout(ctx, time.Millisecond*10)
+		defer cancel()
+
+		Expect(l.Wait(ctx)).Should(MatchError(context.DeadlineExceeded))
+	})
+
+	It("should throttle events with RateLimit handler", func() {
+		c := pipelines.Pipe(pipelines.PassThrough[int]().Pipeline(), pipelines.RateLimit[int](200, 1))
+
+		start := time.Now()
+		count := 0
+		for _, err := range c.HandleSeq(ctx, payloads(11)) {
+			Expect(err).ShouldNot(HaveOccurred())
+			count++
+		}
+
+		Expect(count).To(Equal(11))
+		Expect(time.Since(start)).To(BeNumerically(">=", time.Millisecond*45))
+	})
+
+	It("should throttle stage with WithRateLimit", func() {
+		c := pipelines.Pipe(
+			pipelines.PassThrough[int]().Pipeline(),
+			pipelines.PassThrough[int](),
+			pipelines.WithRateLimit(200, 1),
+			pipelines.WithHandlerPool(4),
+		)
+
+		start := time.Now()
+		count := 0
+		for _, err := range c.HandleSeq(ctx, payloads(11)) {
+			Expect(err).ShouldNot(HaveOccurred())
+			count++
+		}
+
+		Expect(count).To(Equal(11))
+		Expect(time.Since(start)).To(BeNumerically(">=", time.Millisecond*45))
+	})
+
+	It("should limit every key separately", func() {
+		key := func(n int) string { return fmt.Sprint(n % 2) }
+		c := pipelines.Pipe(
+			pipelines.PassThrough[int]().Pipeline(),
+			pipelines.PassThrough[int](),
+			pipelines.WithKeyedRateLimit(1, 1, key),
+			pipelines.WithHandlerPool(2),
+		)
+
+		start := time.Now()
+		count := 0
+		for _, err := range c.HandleSeq(ctx, payloads(2)) {
+			Expect(err).ShouldNot(HaveOccurred())
+			count++
+		}
+
+		Expect(count).To(Equal(2))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Millisecond*100))
+
+		limited := pipelines.Pipe(
+			pipelines.PassThrough[string]().Pipeline(),
+			pipelines.KeyedRateLimit(1, 1, func(s string) string { return s }),
+		)
+
+		ctx, cancel := context.WithTimeout(ctx, time.Millisecond*50)
+		defer cancel()
+
+		values := func(yield func(string) bool) {
+			_ = yield("a") && yield("b") && yield("a")
+		}
+
+		accumulated := []string{}
+		for value, err := range limited.HandleSeq(ctx, values) {
+			if err != nil {
+				break
+			}
+
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(Equal([]string{"a", "b"}))
+	})
+
+	It("should apply stage limit of Pipe2 once per event", func() {
+		limited := pipelines.Pipe2(
+			pipelines.PassThrough[int]().Pipeline(),
+			pipelines.PassThrough[int](),
+			pipelines.PassThrough[int](),
+			pipelines.WithRateLimit(100, 1),
+		)
+
+		start := time.Now()
+		for _, err := range limited.HandleSeq(ctx, payloads(6)) {
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		Expect(time.Since(start)).To(BeNumerically("<", time.Millisecond*90))
+	})
+
+	It("should reject keyed limit of other input type", func() {
+		key := pipelines.WithKeyedRateLimit(1, 1, func(s string) string { return s })
+
+		Expect(func() {
+			pipelines.Pipe(pipelines.PassThrough[int]().Pipeline(), pipelines.PassThrough[int](), key)
+		}).To(Panic())
+		Expect(func() {
+			pipelines.Pipe2(
+				pipelines.PassThrough[int]().Pipeline(),
+				pipelines.Map(func(n int) string { return fmt.Sprint(n) }),
+				pipelines.PassThrough[string](),
+				key,
+			)
+		}).NotTo(Panic())
+	})
+
+	It("should recover panic of rate limit key", func() {
+		c := pipelines.Pipe(
+			pipelines.PassThrough[int]().Pipeline(),
+			pipelines.PassThrough[int](),
+			pipelines.WithKeyedRateLimit(1, 1, func(int) string { panic("no key") }),
+		)
+
+		for _, err := range c.Handle(ctx, 1) {
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("no key"))
+		}
+	})
+})
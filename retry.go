@@ -0,0 +1,177 @@
+package pipelines
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff returns delay to wait after failed attempt before the next one.
+// Attempts are counted from 1.
+type Backoff func(attempt int) time.Duration
+
+// Backoff that waits the same delay after every attempt.
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// Backoff that doubles delay after every attempt starting from base, but never waits longer than max.
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		delay := base
+		for i := 1; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+
+		if delay > max {
+			return max
+		}
+
+		return delay
+	}
+}
+
+// Backoff that waits random delay between zero and the one returned by backoff.
+func JitteredBackoff(backoff Backoff) Backoff {
+	return func(attempt int) time.Duration {
+		delay := backoff(attempt)
+		if delay <= 0 {
+			return 0
+		}
+
+		return time.Duration(rand.Int64N(int64(delay) + 1))
+	}
+}
+
+// RetryPolicy describes how failed Handle or Handler is retried.
+type RetryPolicy struct {
+	// Maximum number of attempts including the first one.
+	// Values below 2 mean there will be no retries.
+	MaxAttempts int
+	// Delay between attempts. No delay if nil.
+	Backoff Backoff
+	// Reports whether error is worth retrying.
+	// If nil, every error is retried except cancelled ones and ones marked with Permanent.
+	// Unmarked errors are retried even though Classify reports them as permanent.
+	Retryable func(error) bool
+}
+
+func (policy RetryPolicy) shouldRetry(attempt int, err error) bool {
+	if attempt >= policy.MaxAttempts {
+		return false
+	}
+
+	if policy.Retryable == nil {
+		return !isMarkedPermanent(err) && !IsCancelled(err)
+	}
+
+	return policy.Retryable(err)
+}
+
+// Waits backoff delay after attempt. Returns ctx error if ctx is done before delay elapsed.
+func (policy RetryPolicy) wait(ctx context.Context, attempt int) error {
+	if policy.Backoff == nil {
+		return ctx.Err()
+	}
+
+	delay := policy.Backoff(attempt)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Returns Handle that calls h again according to policy while it fails.
+// Resulting error is Error[T] with number of attempts made.
+func Retry[T, U any](h Handle[T, U], policy RetryPolicy) Handle[T, U] {
+	return func(ctx context.Context, payload T) (U, error) {
+		for attempt := 1; ; attempt++ {
+			v, err := h(ctx, payload)
+			if err == nil {
+				return v, nil
+			}
+
+			if !policy.shouldRetry(attempt, err) {
+				return zero[U](), newError(err, payload, attempt)
+			}
+
+			if ctxErr := policy.wait(ctx, attempt); ctxErr != nil {
+				return zero[U](), newError(fmt.Errorf("%w: %w", err, ctxErr), payload, attempt)
+			}
+		}
+	}
+}
+
+// Option that makes stage call handler again according to policy while it writes errors.
+// Events written by failed attempt are discarded, so handler events are passed on
+// only after handler returns.
+// Errors of the last attempt are passed on as Error with number of attempts made.
+func WithRetry(policy RetryPolicy) HandlerOptions {
+	return func(o handlerOptions) handlerOptions {
+		o.retry = &policy
+
+		return o
+	}
+}
+
+func withRetry[T, U any](handle Handler[T, U], policy RetryPolicy) Handler[T, U] {
+	return func(ctx context.Context, w EventWriter[U], payload T) {
+		for attempt := 1; ; attempt++ {
+			c := new(eventCollector[U])
+			handle(ctx, c, payload)
+
+			events := c.collected()
+			err := firstError(events)
+
+			if err != nil && policy.shouldRetry(attempt, err) && policy.wait(ctx, attempt) == nil {
+				continue
+			}
+
+			for _, e := range events {
+				if e.Err != nil {
+					w.WriteError(withAttempts(e.Err, payload, attempt))
+
+					continue
+				}
+
+				w.Write(e.Payload)
+			}
+
+			return
+		}
+	}
+}
+
+func firstError[T any](events []Event[T]) error {
+	for _, e := range events {
+		if e.Err != nil {
+			return e.Err
+		}
+	}
+
+	return nil
+}
+
+// Returns Error[T] carrying number of attempts made to process payload.
+func withAttempts[T any](err error, payload T, attempts int) error {
+	if e, ok := err.(*Error[T]); ok {
+		withAttempts := *e
+		withAttempts.Attempts = attempts
+
+		return &withAttempts
+	}
+
+	return newError(err, payload, attempts)
+}
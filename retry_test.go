@@ -0,0 +1,172 @@
+package pipelines_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retry", func() {
+	ctx := context.TODO()
+	errTransient := errors.New("transient")
+	errPermanent := errors.New("permanent")
+
+	failTimes := func(n int, err error) (pipelines.Handle[int, int], *int) {
+		calls := 0
+
+		return func(_ context.Context, v int) (int, error) {
+			calls++
+			if calls <= n {
+				return 0, err
+			}
+
+			return v + 1, nil
+		}, &calls
+	}
+
+	It("should retry Handle until it succeeds", func() {
+		h, calls := failTimes(2, errTransient)
+		fn := pipelines.Retry(h, pipelines.RetryPolicy{MaxAttempts: 3})
+
+		v, err := fn(ctx, 1)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(v).To(Equal(2))
+		Expect(*calls).To(Equal(3))
+	})
+
+	It("should record attempts on resulting Error", func() {
+		h, calls := failTimes(5, errTransient)
+		fn := pipelines.Retry(h, pipelines.RetryPolicy{MaxAttempts: 3})
+
+		_, err := fn(ctx, 1)
+
+		Expect(*calls).To(Equal(3))
+		Expect(err).Should(BeAssignableToTypeOf(new(pipelines.Error[int])))
+		Expect(err.(*pipelines.Error[int]).Attempts).To(Equal(3))
+		Expect(err.(*pipelines.Error[int]).Payload).To(Equal(1))
+		Expect(err).Should(MatchError(errTransient))
+		Expect(err).Should(MatchError("error processing int after 3 attempts: transient"))
+	})
+
+	It("should not retry errors that are not retryable", func() {
+		h, calls := failTimes(5, errPermanent)
+		fn := pipelines.Retry(h, pipelines.RetryPolicy{
+			MaxAttempts: 3,
+			Retryable:   func(err error) bool { return errors.Is(err, errTransient) },
+		})
+
+		_, err := fn(ctx, 1)
+
+		Expect(*calls).To(Equal(1))
+		Expect(err).Should(MatchError(errPermanent))
+		Expect(err.(*pipelines.Error[int]).Attempts).To(Equal(1))
+	})
+
+	It("should record single attempt when policy does not allow retries", func() {
+		h, calls := failTimes(5, errTransient)
+		fn := pipelines.Retry(h, pipelines.RetryPolicy{})
+
+		_, err := fn(ctx, 1)
+
+		Expect(*calls).To(Equal(1))
+		Expect(err.(*pipelines.Error[int]).Attempts).To(Equal(1))
+		Expect(pipelines.NewError(errTransient, 1).(*pipelines.Error[int]).Attempts).To(BeZero())
+	})
+
+	It("should not retry permanent and cancelled errors by default", func() {
+		for _, cause := range []error{pipelines.Permanent(errTransient), fmt.Errorf("stopped: %w", context.Canceled)} {
+			h, calls := failTimes(5, cause)
+			fn := pipelines.Retry(h, pipelines.RetryPolicy{MaxAttempts: 3})
+
+			_, err := fn(ctx, 1)
+
+			Expect(*calls).To(Equal(1))
+			Expect(err).Should(MatchError(cause))
+		}
+	})
+
+	It("should stop waiting for next attempt when ctx is done", func() {
+		h, calls := failTimes(5, errTransient)
+		fn := pipelines.Retry(h, pipelines.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     pipelines.ConstantBackoff(time.Hour),
+		})
+
+		ctx, cancel := context.WithTimeout(ctx, time.Millisecond*10)
+		defer cancel()
+
+		_, err := fn(ctx, 1)
+
+		Expect(*calls).To(Equal(1))
+		Expect(err).Should(MatchError(errTransient))
+		Expect(err).Should(MatchError(context.DeadlineExceeded))
+	})
+
+	It("should calculate backoff", func() {
+		exponential := pipelines.ExponentialBackoff(time.Millisecond, time.Millisecond*5)
+
+		Expect(exponential(1)).To(Equal(time.Millisecond))
+		Expect(exponential(2)).To(Equal(time.Millisecond * 2))
+		Expect(exponential(3)).To(Equal(time.Millisecond * 4))
+		Expect(exponential(4)).To(Equal(time.Millisecond * 5))
+		Expect(exponential(100)).To(Equal(time.Millisecond * 5))
+
+		jittered := pipelines.JitteredBackoff(pipelines.ConstantBackoff(time.Millisecond))
+		for i := 1; i < 10; i++ {
+			Expect(jittered(i)).To(BeNumerically("<=", time.Millisecond))
+			Expect(jittered(i)).To(BeNumerically(">=", 0))
+		}
+	})
+
+	It("should retry stage handler and discard writes of failed attempts", func() {
+		calls := 0
+		handler := func(ctx context.Context, w pipelines.EventWriter[int], e int) {
+			calls++
+			w.Write(calls)
+			if calls < 3 {
+				w.WriteError(errTransient)
+			}
+		}
+
+		c := pipelines.Pipe(
+			pipelines.PassThrough[int]().Pipeline(),
+			handler,
+			pipelines.WithRetry(pipelines.RetryPolicy{
+				MaxAttempts: 3,
+				Backoff:     pipelines.ConstantBackoff(time.Millisecond),
+			}),
+		)
+
+		accumulated := []int{}
+		for value, err := range c.Handle(ctx, 0) {
+			Expect(err).ShouldNot(HaveOccurred())
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(Equal([]int{3}))
+	})
+
+	It("should pass on errors of the last stage handler attempt with attempts count", func() {
+		c := pipelines.Pipe(
+			pipelines.PassThrough[int]().Pipeline(),
+			pipelines.HandleFunc(pipelines.LiftErr(func(context.Context, int) error { return errTransient })),
+			pipelines.WithRetry(pipelines.RetryPolicy{MaxAttempts: 2}),
+		)
+
+		errs := []error{}
+		for _, err := range c.Handle(ctx, 42) {
+			errs = append(errs, err)
+		}
+
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0]).Should(MatchError(errTransient))
+		Expect(errs[0].(*pipelines.Error[int]).Attempts).To(Equal(2))
+		Expect(errs[0].(*pipelines.Error[int]).Payload).To(Equal(42))
+	})
+})
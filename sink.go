@@ -0,0 +1,101 @@
+package pipelines
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Sink receives results of Pipeline execution.
+type Sink[U any] interface {
+	// Writes result.
+	Write(ctx context.Context, v U) error
+}
+
+// SinkFunc is Sink that calls itself with every result.
+type SinkFunc[U any] func(context.Context, U) error
+
+func (fn SinkFunc[U]) Write(ctx context.Context, v U) error {
+	return fn(ctx, v)
+}
+
+// Returns Sink that sends results to ch until ctx is done.
+func ChanSink[U any](ch chan<- U) Sink[U] {
+	return SinkFunc[U](func(ctx context.Context, v U) error {
+		select {
+		case ch <- v:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// Returns Sink that writes every result to w as it is.
+func WriterSink(w io.Writer) Sink[[]byte] {
+	var mu sync.Mutex
+
+	return SinkFunc[[]byte](func(_ context.Context, p []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		_, err := w.Write(p)
+
+		return err
+	})
+}
+
+// Returns Sink that writes every result to w as a line formatted with fmt.Sprint.
+func LinesSink[U any](w io.Writer) Sink[U] {
+	var mu sync.Mutex
+
+	return SinkFunc[U](func(_ context.Context, v U) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		_, err := fmt.Fprintln(w, v)
+
+		return err
+	})
+}
+
+// Returns Sink that writes every result to w as CSV record, e.g. to opened CSV file.
+// configure, if not nil, is called with csv.Writer before writing to set separator and other options.
+func CSVSink(w io.Writer, configure func(*csv.Writer)) Sink[[]string] {
+	var mu sync.Mutex
+
+	writer := csv.NewWriter(w)
+	if configure != nil {
+		configure(writer)
+	}
+
+	return SinkFunc[[]string](func(_ context.Context, record []string) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+
+		writer.Flush()
+
+		return writer.Error()
+	})
+}
+
+// Returns Sink that writes every result to w as JSON line, e.g. to opened JSON-lines file.
+func JSONLinesSink[U any](w io.Writer) Sink[U] {
+	var mu sync.Mutex
+
+	encoder := json.NewEncoder(w)
+
+	return SinkFunc[U](func(_ context.Context, v U) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return encoder.Encode(v)
+	})
+}
@@ -0,0 +1,187 @@
+package pipelines
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+)
+
+// Source produces payloads for Pipeline execution.
+type Source[T any] interface {
+	// Returns payloads to handle.
+	Payloads(ctx context.Context) iter.Seq[T]
+	// Returns error that stopped payloads early, if any.
+	// Should be called after payloads are exhausted.
+	Err() error
+}
+
+// AckSource is Source that wants to know once each of its payloads is handled,
+// e.g. to commit offset of a queue.
+type AckSource[T any] interface {
+	Source[T]
+	// Returns payloads to handle paired with Acknowledger notified once payload is handled.
+	AckPayloads(ctx context.Context) iter.Seq2[T, Acknowledger]
+}
+
+// Handles every payload of source within single Pipeline execution and writes results to sink.
+// Payloads of AckSource are acknowledged once their results are written to sink.
+// Stops at the first error that reaches the end of pipeline, fails source or sink.
+func Run[T, U any](ctx context.Context, source Source[T], pipeline Pipeline[T, U], sink Sink[U], opts ...HandleOptions) error {
+	// source might wait for its next payload, so it should be stopped before execution is torn down
+	deadline := newHandleOptions(opts).executionDeadline()
+	if !deadline.IsZero() {
+		opts = append(opts, WithDeadline(deadline))
+	}
+
+	sourceCtx, cancel := withDeadline(ctx, deadline)
+	defer cancel()
+
+	var results iter.Seq2[U, error]
+	if s, ok := source.(AckSource[T]); ok {
+		results = pipeline.HandleAcked(ctx, s.AckPayloads(sourceCtx), opts...)
+	} else {
+		results = pipeline.HandleSeq(ctx, source.Payloads(sourceCtx), opts...)
+	}
+
+	for v, err := range results {
+		if err != nil {
+			cancel()
+
+			return err
+		}
+
+		if err := sink.Write(ctx, v); err != nil {
+			cancel()
+
+			return err
+		}
+	}
+
+	// execution is finished only once it stops reading payloads, so source error is settled
+	if err := source.Err(); err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// source was stopped by execution deadline before execution noticed it
+	if errors.Is(sourceCtx.Err(), context.DeadlineExceeded) {
+		return ErrPipelineTimeout
+	}
+
+	return nil
+}
+
+// source is Source that reads payloads with read.
+type source[T any] struct {
+	read func(ctx context.Context, yield func(T) bool) error
+	err  error
+}
+
+func (s *source[T]) Payloads(ctx context.Context) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.err = s.read(ctx, yield)
+	}
+}
+
+func (s *source[T]) Err() error {
+	return s.err
+}
+
+// Returns Source of payloads received from ch until it is closed or ctx is done.
+func ChanSource[T any](ch <-chan T) Source[T] {
+	return &source[T]{read: func(ctx context.Context, yield func(T) bool) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case payload, ok := <-ch:
+				if !ok || !yield(payload) {
+					return nil
+				}
+			}
+		}
+	}}
+}
+
+// Returns Source of payloads of seq.
+func SeqSource[T any](seq iter.Seq[T]) Source[T] {
+	return &source[T]{read: func(_ context.Context, yield func(T) bool) error {
+		for payload := range seq {
+			if !yield(payload) {
+				return nil
+			}
+		}
+
+		return nil
+	}}
+}
+
+// Returns Source of lines read from r without line endings.
+func LinesSource(r io.Reader) Source[string] {
+	return &source[string]{read: func(_ context.Context, yield func(string) bool) error {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if !yield(scanner.Text()) {
+				return nil
+			}
+		}
+
+		return scanner.Err()
+	}}
+}
+
+// Returns Source of CSV records read from r, e.g. opened CSV file.
+// configure, if not nil, is called with csv.Reader before reading to set separator and other options.
+func CSVSource(r io.Reader, configure func(*csv.Reader)) Source[[]string] {
+	return &source[[]string]{read: func(_ context.Context, yield func([]string) bool) error {
+		reader := csv.NewReader(r)
+		if configure != nil {
+			configure(reader)
+		}
+
+		for {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if !yield(record) {
+				return nil
+			}
+		}
+	}}
+}
+
+// Returns Source of values decoded from JSON lines read from r, e.g. opened JSON-lines file.
+func JSONLinesSource[T any](r io.Reader) Source[T] {
+	return &source[T]{read: func(_ context.Context, yield func(T) bool) error {
+		decoder := json.NewDecoder(r)
+		for {
+			var payload T
+
+			err := decoder.Decode(&payload)
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if !yield(payload) {
+				return nil
+			}
+		}
+	}}
+}
@@ -0,0 +1,192 @@
+package pipelines_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type ackedSource struct {
+	pipelines.Source[int]
+
+	acked  []int
+	nacked []int
+}
+
+func (s *ackedSource) AckPayloads(ctx context.Context) iter.Seq2[int, pipelines.Acknowledger] {
+	return func(yield func(int, pipelines.Acknowledger) bool) {
+		for payload := range s.Payloads(ctx) {
+			ack := pipelines.AckFuncs{
+				OnAck:  func() { s.acked = append(s.acked, payload) },
+				OnNack: func(error) { s.nacked = append(s.nacked, payload) },
+			}
+			if !yield(payload, ack) {
+				return
+			}
+		}
+	}
+}
+
+var _ = Describe("Run", func() {
+	ctx := context.TODO()
+
+	double := pipelines.Handler[int, int](func(ctx context.Context, w pipelines.EventWriter[int], e int) {
+		w.Write(e * 2)
+	}).Pipeline()
+
+	It("should read lines and write results", func() {
+		c := pipelines.Pipe(
+			pipelines.PassThrough[string]().Pipeline(),
+			pipelines.HandleFunc(func(ctx context.Context, line string) (int, error) {
+				return strconv.Atoi(line)
+			}),
+		)
+
+		var out bytes.Buffer
+		err := pipelines.Run(ctx, pipelines.LinesSource(strings.NewReader("1\n2\n3\n")), c, pipelines.LinesSink[int](&out))
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out.String()).To(Equal("1\n2\n3\n"))
+	})
+
+	It("should convert CSV records to JSON lines", func() {
+		type person struct {
+			Name string `json:"name"`
+			Age  int    `json:"age"`
+		}
+
+		c := pipelines.Pipe(
+			pipelines.PassThrough[[]string]().Pipeline(),
+			pipelines.HandleFunc(func(ctx context.Context, record []string) (person, error) {
+				age, err := strconv.Atoi(record[1])
+
+				return person{Name: record[0], Age: age}, err
+			}),
+		)
+
+		var out bytes.Buffer
+		source := pipelines.CSVSource(strings.NewReader("ann;31\nbob;42\n"), func(r *csv.Reader) { r.Comma = ';' })
+		err := pipelines.Run(ctx, source, c, pipelines.JSONLinesSink[person](&out))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out.String()).To(Equal("{\"name\":\"ann\",\"age\":31}\n{\"name\":\"bob\",\"age\":42}\n"))
+
+		var records bytes.Buffer
+		err = pipelines.Run(
+			ctx,
+			pipelines.JSONLinesSource[person](&out),
+			pipelines.Pipe(pipelines.PassThrough[person]().Pipeline(), pipelines.Map(func(p person) []string {
+				return []string{p.Name, strconv.Itoa(p.Age)}
+			})),
+			pipelines.CSVSink(&records, nil),
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(records.String()).To(Equal("ann,31\nbob,42\n"))
+	})
+
+	It("should pass payloads between channels", func() {
+		in := make(chan int)
+		out := make(chan int, 3)
+
+		go func() {
+			defer close(in)
+
+			for i := 1; i <= 3; i++ {
+				in <- i
+			}
+		}()
+
+		Expect(pipelines.Run(ctx, pipelines.ChanSource(in), double, pipelines.ChanSink(out))).Should(Succeed())
+		close(out)
+
+		results := []int{}
+		for v := range out {
+			results = append(results, v)
+		}
+
+		Expect(results).To(ConsistOf(2, 4, 6))
+	})
+
+	It("should stop at source, pipeline or sink error", func() {
+		source := pipelines.JSONLinesSource[int](strings.NewReader("1\n{"))
+		sink := pipelines.SinkFunc[int](func(context.Context, int) error { return nil })
+
+		Expect(pipelines.Run(ctx, source, double, sink)).Should(MatchError("unexpected EOF"))
+
+		failing := pipelines.Pipe(double, pipelines.HandleFunc(func(ctx context.Context, e int) (int, error) {
+			return 0, fmt.Errorf("failed")
+		}))
+		err := pipelines.Run(ctx, pipelines.SeqSource(slices.Values([]int{1})), failing, sink)
+		Expect(err).Should(MatchError("error processing int: failed"))
+
+		failingSink := pipelines.SinkFunc[int](func(context.Context, int) error { return fmt.Errorf("full") })
+		err = pipelines.Run(ctx, pipelines.SeqSource(slices.Values([]int{1})), double, failingSink)
+		Expect(err).Should(MatchError("full"))
+	})
+
+	It("should stop idle source on sink error or execution deadline", func() {
+		in := make(chan int, 1)
+		in <- 1
+
+		failingSink := pipelines.SinkFunc[int](func(context.Context, int) error { return fmt.Errorf("full") })
+		done := make(chan error)
+		go func() {
+			done <- pipelines.Run(ctx, pipelines.ChanSource(in), double, failingSink)
+		}()
+
+		Eventually(done).Should(Receive(MatchError("full")))
+
+		sink := pipelines.SinkFunc[int](func(context.Context, int) error { return nil })
+		go func() {
+			done <- pipelines.Run(
+				ctx, pipelines.ChanSource(in), double, sink,
+				pipelines.WithExecutionTimeout(time.Millisecond*20),
+			)
+		}()
+
+		Eventually(done).Should(Receive(MatchError(pipelines.ErrPipelineTimeout)))
+	})
+
+	It("should finish reading source when pipeline stops early", func() {
+		infinite := func(yield func(int) bool) {
+			for i := 0; yield(i); i++ {
+			}
+		}
+
+		written := []int{}
+		sink := pipelines.SinkFunc[int](func(_ context.Context, v int) error {
+			written = append(written, v)
+
+			return nil
+		})
+
+		Expect(pipelines.Run(
+			ctx, pipelines.SeqSource(infinite), pipelines.Take(pipelines.PassThrough[int]().Pipeline(), 3), sink,
+		)).Should(Succeed())
+		Expect(written).To(Equal([]int{0, 1, 2}))
+	})
+
+	It("should acknowledge payloads of AckSource", func() {
+		source := &ackedSource{Source: pipelines.SeqSource(slices.Values([]int{1, 2, 3}))}
+
+		var out bytes.Buffer
+		Expect(pipelines.Run(ctx, source, double, pipelines.LinesSink[int](&out))).Should(Succeed())
+		Expect(source.acked).To(ConsistOf(1, 2, 3))
+
+		source = &ackedSource{Source: pipelines.SeqSource(slices.Values([]int{1}))}
+		failingSink := pipelines.SinkFunc[int](func(context.Context, int) error { return fmt.Errorf("sink down") })
+
+		Expect(pipelines.Run(ctx, source, double, failingSink)).Should(MatchError("sink down"))
+		Expect(source.acked).To(BeEmpty())
+		Expect(source.nacked).To(ConsistOf(1))
+	})
+})
@@ -0,0 +1,428 @@
+package pipelines
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// StateStore keeps state of StatefulHandler by key.
+type StateStore[S any] interface {
+	// Returns state stored for key and whether it exists.
+	Load(ctx context.Context, key string) (S, bool, error)
+	// Stores state for key.
+	Store(ctx context.Context, key string, state S) error
+	// Removes state stored for key.
+	Delete(ctx context.Context, key string) error
+	Snapshotter
+}
+
+// Snapshotter saves and restores its whole state.
+type Snapshotter interface {
+	// Writes every stored state to w.
+	Snapshot(ctx context.Context, w io.Writer) error
+	// Replaces every stored state with states read from r written by Snapshot.
+	Restore(ctx context.Context, r io.Reader) error
+}
+
+// State of single key passed to StatefulHandler.
+type State[S any] struct {
+	ctx   context.Context
+	store StateStore[S]
+	key   string
+}
+
+// Returns state key.
+func (s *State[S]) Key() string {
+	return s.key
+}
+
+// Returns current state and whether it exists.
+func (s *State[S]) Load() (S, bool, error) {
+	return s.store.Load(s.ctx, s.key)
+}
+
+// Replaces current state with state.
+func (s *State[S]) Store(state S) error {
+	return s.store.Store(s.ctx, s.key, state)
+}
+
+// Removes current state.
+func (s *State[S]) Delete() error {
+	return s.store.Delete(s.ctx, s.key)
+}
+
+// StatefulHandler is used to handle particular event with state of the event key.
+type StatefulHandler[T, U, S any] func(context.Context, EventWriter[U], T, *State[S])
+
+// Returns Handler that passes h state of payload key kept in store.
+// Events with the same key are handled one at a time.
+func (h StatefulHandler[T, U, S]) Handler(store StateStore[S], key func(T) string) Handler[T, U] {
+	locks := &keyLocks{locks: make(map[string]*keyLock)}
+
+	return func(ctx context.Context, w EventWriter[U], payload T) {
+		k := key(payload)
+
+		unlock := locks.lock(k)
+		defer unlock()
+
+		h(ctx, w, payload, &State[S]{ctx: ctx, store: store, key: k})
+	}
+}
+
+type keyLock struct {
+	sync.Mutex
+
+	refs int
+}
+
+// keyLocks holds mutex per key while it is in use.
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+func (l *keyLocks) lock(key string) func() {
+	l.mu.Lock()
+	kl, ok := l.locks[key]
+	if !ok {
+		kl = new(keyLock)
+		l.locks[key] = kl
+	}
+
+	kl.refs++
+	l.mu.Unlock()
+
+	kl.Lock()
+
+	return func() {
+		kl.Unlock()
+
+		l.mu.Lock()
+		if kl.refs--; kl.refs == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// Entry of state snapshot and file state log.
+type stateRecord[S any] struct {
+	Key     string `json:"key"`
+	State   S      `json:"state,omitempty"`
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+func writeStates[S any](w io.Writer, states map[string]S) error {
+	encoder := json.NewEncoder(w)
+	for _, key := range slices.Sorted(maps.Keys(states)) {
+		if err := encoder.Encode(stateRecord[S]{Key: key, State: states[key]}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Replays records read from r on states.
+func readStates[S any](r io.Reader, states map[string]S) error {
+	decoder := json.NewDecoder(r)
+	for {
+		var record stateRecord[S]
+
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if record.Deleted {
+			delete(states, record.Key)
+
+			continue
+		}
+
+		states[record.Key] = record.State
+	}
+}
+
+// Returns StateStore that keeps states in memory.
+func NewMemoryStateStore[S any]() *MemoryStateStore[S] {
+	return &MemoryStateStore[S]{states: make(map[string]S)}
+}
+
+// StateStore that keeps states in memory.
+type MemoryStateStore[S any] struct {
+	mu     sync.RWMutex
+	states map[string]S
+}
+
+func (s *MemoryStateStore[S]) Load(_ context.Context, key string) (S, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state, ok := s.states[key]
+
+	return state, ok, nil
+}
+
+func (s *MemoryStateStore[S]) Store(_ context.Context, key string, state S) error {
+	s.mu.Lock()
+	s.states[key] = state
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *MemoryStateStore[S]) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	delete(s.states, key)
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *MemoryStateStore[S]) Snapshot(_ context.Context, w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return writeStates(w, s.states)
+}
+
+func (s *MemoryStateStore[S]) Restore(_ context.Context, r io.Reader) error {
+	states := make(map[string]S)
+	if err := readStates(r, states); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.states = states
+	s.mu.Unlock()
+
+	return nil
+}
+
+// FileStateStoreOptions receives current FileStateStore options and returns updated ones.
+type FileStateStoreOptions func(fileStateStoreOptions) fileStateStoreOptions
+
+type fileStateStoreOptions struct {
+	sync bool
+}
+
+// Option that makes FileStateStore sync the file after every change,
+// so stored states survive operating system crash or power loss at the cost of slower writes.
+func WithSyncedWrites() FileStateStoreOptions {
+	return func(o fileStateStoreOptions) fileStateStoreOptions {
+		o.sync = true
+
+		return o
+	}
+}
+
+// Returns StateStore that keeps states in memory and appends every change to the file at path.
+// States logged to the file before are loaded.
+// Partial record left at the end of the file by interrupted write is discarded.
+// File is created if it does not exist.
+func OpenFileStateStore[S any](path string, opts ...FileStateStoreOptions) (*FileStateStore[S], error) {
+	var o fileStateStoreOptions
+	for _, option := range opts {
+		o = option(o)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]S)
+	if err := replayStates(f, states); err != nil {
+		f.Close()
+
+		return nil, err
+	}
+
+	return &FileStateStore[S]{path: path, f: f, encoder: json.NewEncoder(f), states: states, sync: o.sync}, nil
+}
+
+// Replays records logged to f on states.
+// Truncates f to the last complete record if write of the next one was interrupted.
+func replayStates[S any](f *os.File, states map[string]S) error {
+	r := bufio.NewReader(f)
+
+	var offset int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				return f.Truncate(offset)
+			}
+
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := readStates(bytes.NewReader(line), states); err != nil {
+			return err
+		}
+
+		offset += int64(len(line))
+	}
+}
+
+// StateStore that keeps states in memory and appends every change to a file.
+// Changes are written to the file before Store or Delete returns, so they survive process crash,
+// but are not synced to disk unless WithSyncedWrites is used.
+type FileStateStore[S any] struct {
+	mu      sync.RWMutex
+	path    string
+	f       *os.File
+	encoder *json.Encoder
+	states  map[string]S
+	sync    bool
+}
+
+func (s *FileStateStore[S]) Load(_ context.Context, key string) (S, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state, ok := s.states[key]
+
+	return state, ok, nil
+}
+
+func (s *FileStateStore[S]) Store(_ context.Context, key string, state S) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.append(stateRecord[S]{Key: key, State: state}); err != nil {
+		return err
+	}
+
+	s.states[key] = state
+
+	return nil
+}
+
+func (s *FileStateStore[S]) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.append(stateRecord[S]{Key: key, Deleted: true}); err != nil {
+		return err
+	}
+
+	delete(s.states, key)
+
+	return nil
+}
+
+// Appends record to the file.
+// Should be called with mu locked.
+func (s *FileStateStore[S]) append(record stateRecord[S]) error {
+	if err := s.encoder.Encode(record); err != nil {
+		return err
+	}
+
+	if s.sync {
+		return s.f.Sync()
+	}
+
+	return nil
+}
+
+func (s *FileStateStore[S]) Snapshot(_ context.Context, w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return writeStates(w, s.states)
+}
+
+// Replaces every stored state with states read from r and rewrites the file with them.
+func (s *FileStateStore[S]) Restore(_ context.Context, r io.Reader) error {
+	states := make(map[string]S)
+	if err := readStates(r, states); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.rewrite(states); err != nil {
+		return err
+	}
+
+	s.states = states
+
+	return nil
+}
+
+// Rewrites the file with current states only, dropping history of changes.
+func (s *FileStateStore[S]) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rewrite(s.states)
+}
+
+// Replaces the file with one holding only states.
+// Should be called with mu locked.
+func (s *FileStateStore[S]) rewrite(states map[string]S) error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if err := writeStates(tmp, states); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	s.f.Close()
+	s.f, s.encoder = f, json.NewEncoder(f)
+
+	return nil
+}
+
+// Closes the file.
+func (s *FileStateStore[S]) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.f.Close()
+}
@@ -0,0 +1,148 @@
+package pipelines_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type deposit struct {
+	account string
+	amount  int
+}
+
+var _ = Describe("State", func() {
+	ctx := context.TODO()
+
+	deposits := []deposit{{"a", 1}, {"b", 10}, {"a", 2}, {"a", 3}, {"b", 20}}
+
+	balance := pipelines.StatefulHandler[deposit, int, int](
+		func(ctx context.Context, w pipelines.EventWriter[int], e deposit, state *pipelines.State[int]) {
+			total, _, err := state.Load()
+			if err != nil {
+				w.WriteError(pipelines.NewError(err, e))
+
+				return
+			}
+
+			total += e.amount
+			if err := state.Store(total); err != nil {
+				w.WriteError(pipelines.NewError(err, e))
+
+				return
+			}
+
+			w.Write(total)
+		},
+	)
+	account := func(e deposit) string { return e.account }
+
+	run := func(store pipelines.StateStore[int]) {
+		c := pipelines.Pipe(
+			pipelines.PassThrough[deposit]().Pipeline(),
+			balance.Handler(store, account),
+			pipelines.WithHandlerPool(4),
+		)
+
+		for _, err := range c.HandleSeq(ctx, slices.Values(deposits)) {
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+	}
+
+	It("should keep state by key in memory", func() {
+		store := pipelines.NewMemoryStateStore[int]()
+		run(store)
+		run(store)
+
+		a, ok, err := store.Load(ctx, "a")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(a).To(Equal(12))
+
+		b, _, _ := store.Load(ctx, "b")
+		Expect(b).To(Equal(60))
+	})
+
+	It("should snapshot and restore state", func() {
+		store := pipelines.NewMemoryStateStore[int]()
+		run(store)
+
+		var snapshot bytes.Buffer
+		Expect(store.Snapshot(ctx, &snapshot)).Should(Succeed())
+
+		run(store)
+		Expect(store.Delete(ctx, "b")).Should(Succeed())
+		Expect(store.Restore(ctx, bytes.NewReader(snapshot.Bytes()))).Should(Succeed())
+
+		a, _, _ := store.Load(ctx, "a")
+		Expect(a).To(Equal(6))
+
+		b, ok, _ := store.Load(ctx, "b")
+		Expect(ok).To(BeTrue())
+		Expect(b).To(Equal(30))
+	})
+
+	It("should keep state in file between store instances", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "state.jsonl")
+
+		store, err := pipelines.OpenFileStateStore[int](path)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		run(store)
+		Expect(store.Delete(ctx, "b")).Should(Succeed())
+		Expect(store.Compact()).Should(Succeed())
+		run(store)
+		Expect(store.Close()).Should(Succeed())
+
+		store, err = pipelines.OpenFileStateStore[int](path)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		defer store.Close()
+
+		a, _, _ := store.Load(ctx, "a")
+		Expect(a).To(Equal(12))
+
+		b, _, _ := store.Load(ctx, "b")
+		Expect(b).To(Equal(30))
+
+		var snapshot bytes.Buffer
+		Expect(store.Snapshot(ctx, &snapshot)).Should(Succeed())
+		Expect(snapshot.String()).To(Equal("{\"key\":\"a\",\"state\":12}\n{\"key\":\"b\",\"state\":30}\n"))
+	})
+
+	It("should discard record torn by interrupted write", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "state.jsonl")
+		Expect(os.WriteFile(path, []byte("{\"key\":\"a\",\"state\":1}\n{\"key\":\"b\",\"st"), 0o644)).Should(Succeed())
+
+		store, err := pipelines.OpenFileStateStore[int](path, pipelines.WithSyncedWrites())
+		Expect(err).ShouldNot(HaveOccurred())
+
+		a, _, _ := store.Load(ctx, "a")
+		Expect(a).To(Equal(1))
+
+		_, ok, _ := store.Load(ctx, "b")
+		Expect(ok).To(BeFalse())
+
+		Expect(store.Store(ctx, "b", 2)).Should(Succeed())
+		Expect(store.Close()).Should(Succeed())
+
+		store, err = pipelines.OpenFileStateStore[int](path)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		defer store.Close()
+
+		b, _, _ := store.Load(ctx, "b")
+		Expect(b).To(Equal(2))
+
+		Expect(os.WriteFile(path, []byte("{\"key\":\n{\"key\":\"a\",\"state\":1}\n"), 0o644)).Should(Succeed())
+
+		_, err = pipelines.OpenFileStateStore[int](path)
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,149 @@
+package pipelines
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrHandlerTimeout  = errors.New("handler timed out")
+	ErrPipelineTimeout = fmt.Errorf("pipeline execution timed out: %w", context.DeadlineExceeded)
+)
+
+// Option that limits how long stage handler can handle a single event.
+// Handler context is cancelled once timeout elapses and Error with ErrHandlerTimeout and the payload is passed on.
+// Events handler writes after that are discarded.
+// Handler that ignores its context keeps running in background until it returns.
+func WithTimeout(timeout time.Duration) HandlerOptions {
+	return func(o handlerOptions) handlerOptions {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+
+		return o
+	}
+}
+
+// Option that stops Pipeline execution at deadline.
+// If execution is stopped by deadline the last result is ErrPipelineTimeout.
+func WithDeadline(deadline time.Time) HandleOptions {
+	return func(o handleOptions) handleOptions {
+		o.deadline = deadline
+
+		return o
+	}
+}
+
+// Option that stops Pipeline execution once timeout elapses since its start.
+// If execution is stopped by timeout the last result is ErrPipelineTimeout.
+func WithExecutionTimeout(timeout time.Duration) HandleOptions {
+	return func(o handleOptions) handleOptions {
+		o.timeout = timeout
+
+		return o
+	}
+}
+
+// Returns cancellable ctx that is also done at deadline, unless deadline is zero.
+func withDeadline(ctx context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	if deadline.IsZero() {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithDeadline(ctx, deadline)
+}
+
+func withTimeout[T, U any](handle Handler[T, U], timeout time.Duration) Handler[T, U] {
+	return func(ctx context.Context, w EventWriter[U], payload T) {
+		handlerCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		var inTime atomic.Bool
+		finished := make(chan struct{})
+
+		go func() {
+			defer close(finished)
+
+			handle(handlerCtx, &timeoutWriter[U]{EventWriter: w, ctx: handlerCtx}, payload)
+			inTime.Store(handlerCtx.Err() == nil)
+		}()
+
+		select {
+		case <-finished:
+		case <-handlerCtx.Done():
+		}
+
+		select {
+		case <-finished:
+			if inTime.Load() {
+				return
+			}
+		default:
+		}
+
+		// parent cancellation means Pipeline execution is stopped
+		if ctx.Err() == nil {
+			w.WriteError(NewError(ErrHandlerTimeout, payload))
+		}
+	}
+}
+
+// timeoutWriter discards events once handler ctx is done,
+// including ones handler is blocked writing.
+type timeoutWriter[T any] struct {
+	EventWriter[T]
+
+	ctx context.Context
+}
+
+func (w *timeoutWriter[T]) Write(e T) {
+	_ = w.WriteContext(w.ctx, e)
+}
+
+func (w *timeoutWriter[T]) TryWrite(e T) error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+
+	return asContextWriter(w.EventWriter).TryWrite(e)
+}
+
+func (w *timeoutWriter[T]) WriteContext(ctx context.Context, e T) error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+
+	ctx, cancel := w.bound(ctx)
+	defer cancel()
+
+	return asContextWriter(w.EventWriter).WriteContext(ctx, e)
+}
+
+func (w *timeoutWriter[T]) WriteError(err error) {
+	_ = w.writeErrorContext(w.ctx, err)
+}
+
+func (w *timeoutWriter[T]) writeErrorContext(ctx context.Context, err error) error {
+	if ctxErr := w.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	ctx, cancel := w.bound(ctx)
+	defer cancel()
+
+	return writeErrorContext(ctx, w.EventWriter, err)
+}
+
+// Returns ctx that is also done once handler ctx is done.
+func (w *timeoutWriter[T]) bound(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(w.ctx, cancel)
+
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
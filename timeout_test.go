@@ -0,0 +1,212 @@
+package pipelines_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Timeout", func() {
+	ctx := context.TODO()
+
+	It("should report handler timeout with payload", func() {
+		stuck := func(ctx context.Context, w pipelines.EventWriter[int], n int) {
+			if n == 1 {
+				<-ctx.Done()
+				w.Write(n)
+
+				return
+			}
+
+			w.Write(n)
+		}
+
+		c := pipelines.Pipe(
+			pipelines.PassThrough[int]().Pipeline(),
+			stuck,
+			pipelines.WithTimeout(time.Millisecond*20),
+		)
+
+		payloads := func(yield func(int) bool) {
+			_ = yield(1) && yield(2)
+		}
+
+		accumulated := []int{}
+		errs := []error{}
+		for value, err := range c.HandleSeq(ctx, payloads) {
+			if err != nil {
+				errs = append(errs, err)
+
+				continue
+			}
+
+			accumulated = append(accumulated, value)
+		}
+
+		Expect(accumulated).To(Equal([]int{2}))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0]).Should(MatchError(pipelines.ErrHandlerTimeout))
+
+		var e *pipelines.Error[int]
+		Expect(errors.As(errs[0], &e)).Should(BeTrue())
+		Expect(e.Payload).To(Equal(1))
+	})
+
+	It("should not wait for handler ignoring its context", func() {
+		gate := make(chan struct{})
+		defer close(gate)
+
+		stuck := func(ctx context.Context, w pipelines.EventWriter[int], n int) {
+			<-gate
+			w.Write(n)
+		}
+
+		c := pipelines.Pipe(
+			pipelines.PassThrough[int]().Pipeline(),
+			stuck,
+			pipelines.WithTimeout(time.Millisecond*20),
+		)
+
+		start := time.Now()
+		for _, err := range c.Handle(ctx, 1) {
+			Expect(err).Should(MatchError(pipelines.ErrHandlerTimeout))
+		}
+
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+
+	It("should discard event handler is blocked writing once timeout elapses", func() {
+		returned := make(chan time.Duration, 2)
+		start := time.Now()
+
+		write := func(ctx context.Context, w pipelines.EventWriter[int], n int) {
+			w.Write(n)
+			returned <- time.Since(start)
+		}
+
+		c := pipelines.Pipe(
+			pipelines.PassThrough[int]().Pipeline(),
+			write,
+			pipelines.WithTimeout(time.Millisecond*20),
+		)
+
+		results := []any{}
+		for value, err := range c.HandleSeq(ctx, slices.Values([]int{1, 2})) {
+			if err != nil {
+				results = append(results, err)
+
+				continue
+			}
+
+			results = append(results, value)
+
+			// slow downstream: second event can't be written until first one is consumed
+			time.Sleep(time.Millisecond * 200)
+		}
+
+		Expect(results).To(HaveLen(2))
+		Expect(results[0]).To(Equal(1))
+		Expect(results[1]).To(MatchError(pipelines.ErrHandlerTimeout))
+
+		<-returned
+		Expect(<-returned).To(BeNumerically("<", time.Millisecond*150))
+	})
+
+	It("should discard error handler is blocked writing once timeout elapses", func() {
+		write := func(ctx context.Context, w pipelines.EventWriter[int], n int) {
+			w.Write(n)
+			w.WriteError(errors.New("late"))
+		}
+
+		c := pipelines.Pipe(
+			pipelines.PassThrough[int]().Pipeline(),
+			write,
+			pipelines.WithTimeout(time.Millisecond*20),
+		)
+
+		results := []any{}
+		for value, err := range c.Handle(ctx, 1) {
+			if err != nil {
+				results = append(results, err)
+
+				continue
+			}
+
+			results = append(results, value)
+
+			// slow downstream: error can't be written until event is consumed
+			time.Sleep(time.Millisecond * 100)
+		}
+
+		Expect(results).To(HaveLen(2))
+		Expect(results[0]).To(Equal(1))
+		Expect(results[1]).To(MatchError(pipelines.ErrHandlerTimeout))
+	})
+
+	It("should retry timed out handler", func() {
+		var calls atomic.Int64
+		slowOnce := func(ctx context.Context, w pipelines.EventWriter[int], n int) {
+			if calls.Add(1) == 1 {
+				<-ctx.Done()
+			}
+
+			w.Write(n)
+		}
+
+		c := pipelines.Pipe(
+			pipelines.PassThrough[int]().Pipeline(),
+			slowOnce,
+			pipelines.WithTimeout(time.Millisecond*20),
+			pipelines.WithRetry(pipelines.RetryPolicy{
+				MaxAttempts: 2,
+				Retryable:   func(err error) bool { return errors.Is(err, pipelines.ErrHandlerTimeout) },
+			}),
+		)
+
+		for value, err := range c.Handle(ctx, 1) {
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).To(Equal(1))
+		}
+
+		Expect(calls.Load()).To(Equal(int64(2)))
+	})
+
+	It("should stop pipeline execution at deadline", func() {
+		payloads := func(yield func(int) bool) {
+			for i := 0; ; i++ {
+				if !yield(i) {
+					return
+				}
+
+				time.Sleep(time.Millisecond)
+			}
+		}
+
+		c := pipelines.PassThrough[int]().Pipeline()
+
+		var last error
+		count := 0
+		for _, err := range c.HandleSeq(ctx, payloads, pipelines.WithExecutionTimeout(time.Millisecond*50)) {
+			last = err
+			count++
+		}
+
+		Expect(count).To(BeNumerically(">", 1))
+		Expect(last).Should(MatchError(pipelines.ErrPipelineTimeout))
+		Expect(last).Should(MatchError(context.DeadlineExceeded))
+
+		count = 0
+		for _, err := range c.Handle(ctx, 1, pipelines.WithDeadline(time.Now().Add(time.Second))) {
+			Expect(err).ShouldNot(HaveOccurred())
+			count++
+		}
+
+		Expect(count).To(Equal(1))
+	})
+})
@@ -0,0 +1,273 @@
+package pipelines
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var ErrLateEvent = errors.New("event arrived after its window was closed")
+
+// Window of events passed on by window stages.
+type Window[T any] struct {
+	// Window start, inclusive.
+	Start time.Time
+	// Window end, exclusive.
+	End time.Time
+	// Events that belong to window.
+	Events []T
+}
+
+// WindowOptions receives current window stage options and returns updated ones.
+type WindowOptions[T any] func(windowOptions[T]) windowOptions[T]
+
+type windowOptions[T any] struct {
+	eventTime func(T) time.Time
+	lateness  time.Duration
+}
+
+// Option that assigns events to windows by time eventTime returns for them
+// instead of time they arrive at.
+// Window is closed once event with time past window end and allowed lateness arrives,
+// or once there are no more events.
+func WithEventTime[T any](eventTime func(T) time.Time) WindowOptions[T] {
+	return func(o windowOptions[T]) windowOptions[T] {
+		o.eventTime = eventTime
+
+		return o
+	}
+}
+
+// Option that keeps window open for lateness after event time passes its end,
+// so out of order events still get into it.
+// Has no effect without WithEventTime.
+func WithAllowedLateness[T any](lateness time.Duration) WindowOptions[T] {
+	return func(o windowOptions[T]) windowOptions[T] {
+		if lateness > 0 {
+			o.lateness = lateness
+		}
+
+		return o
+	}
+}
+
+// Returns `Pipeline[T, Window[U]]` that groups events of p into consecutive windows of size.
+// Windows are aligned to Unix epoch, empty windows are not passed on.
+// Errors are passed on as they arrive, late events are passed on as Error with ErrLateEvent and the event.
+// Panics if size is not positive.
+func TumblingWindow[T, U any](p Pipeline[T, U], size time.Duration, opts ...WindowOptions[U]) Pipeline[T, Window[U]] {
+	return SlidingWindow(p, size, size, opts...)
+}
+
+// Returns `Pipeline[T, Window[U]]` that groups events of p into windows of size starting every slide,
+// so every event belongs to size/slide windows.
+// Windows are aligned to Unix epoch, empty windows are not passed on.
+// Errors are passed on as they arrive, late events are passed on as Error with ErrLateEvent and the event.
+// Panics if size or slide is not positive or slide is greater than size.
+func SlidingWindow[T, U any](p Pipeline[T, U], size, slide time.Duration, opts ...WindowOptions[U]) Pipeline[T, Window[U]] {
+	if size <= 0 || slide <= 0 || slide > size {
+		panic(fmt.Sprintf("pipelines: invalid window size %v and slide %v", size, slide))
+	}
+
+	return window(p, opts, func(open []*openWindow[U], payload U, a *ack, t, watermark time.Time) ([]*openWindow[U], bool) {
+		late := true
+		for start := alignToEpoch(t, slide); start.Add(size).After(t); start = start.Add(-slide) {
+			end := start.Add(size)
+			if !end.After(watermark) {
+				continue
+			}
+
+			late = false
+
+			i := slices.IndexFunc(open, func(w *openWindow[U]) bool { return w.Start.Equal(start) })
+			if i < 0 {
+				open = append(open, &openWindow[U]{Window: Window[U]{Start: start, End: end}})
+				i = len(open) - 1
+			}
+
+			open[i].add(payload, a)
+		}
+
+		return open, late
+	})
+}
+
+// Returns `Pipeline[T, Window[U]]` that groups events of p into sessions:
+// session window is extended by every event that arrives less than gap after its end.
+// Errors are passed on as they arrive, late events are passed on as Error with ErrLateEvent and the event.
+// Panics if gap is not positive.
+func SessionWindow[T, U any](p Pipeline[T, U], gap time.Duration, opts ...WindowOptions[U]) Pipeline[T, Window[U]] {
+	if gap <= 0 {
+		panic(fmt.Sprintf("pipelines: invalid session gap %v", gap))
+	}
+
+	return window(p, opts, func(open []*openWindow[U], payload U, a *ack, t, watermark time.Time) ([]*openWindow[U], bool) {
+		session := &openWindow[U]{Window: Window[U]{Start: t, End: t.Add(gap)}}
+		if !session.End.After(watermark) {
+			return open, true
+		}
+
+		// event may join several sessions into one
+		open = slices.DeleteFunc(open, func(w *openWindow[U]) bool {
+			if !w.Start.Before(session.End) || !session.Start.Before(w.End) {
+				return false
+			}
+
+			if w.Start.Before(session.Start) {
+				session.Start = w.Start
+			}
+
+			if w.End.After(session.End) {
+				session.End = w.End
+			}
+
+			session.Events = append(session.Events, w.Events...)
+			session.acks = append(session.acks, w.acks...)
+
+			return true
+		})
+
+		session.add(payload, a)
+
+		return append(open, session), false
+	})
+}
+
+// Returns start of window of size t belongs to, counting windows from Unix epoch.
+func alignToEpoch(t time.Time, size time.Duration) time.Time {
+	offset := time.Duration(t.UnixNano() % int64(size))
+	if offset < 0 {
+		offset += size
+	}
+
+	// monotonic clock reading is stripped, so starts of the same window are equal
+	return t.Round(0).Add(-offset)
+}
+
+// Adds event with time t to open windows.
+// Returns updated open windows and whether event is late for every window it belongs to.
+type windowAssign[T any] func(open []*openWindow[T], payload T, a *ack, t, watermark time.Time) ([]*openWindow[T], bool)
+
+// openWindow is Window that is not passed on yet.
+type openWindow[T any] struct {
+	Window[T]
+
+	acks []*ack
+}
+
+func (w *openWindow[T]) add(payload T, a *ack) {
+	a.derive()
+	w.Events = append(w.Events, payload)
+	w.acks = append(w.acks, a)
+}
+
+func window[T, U any](p Pipeline[T, U], opts []WindowOptions[U], assign windowAssign[U]) Pipeline[T, Window[U]] {
+	var o windowOptions[U]
+	for _, option := range opts {
+		o = option(o)
+	}
+
+	return func(ctx context.Context) (EventWriterCloser[T], EventReader[Window[U]], int) {
+		w, r, pool := p(ctx)
+		rw := newEventRW[Window[U]](ctx, 0)
+		out := rw.GetWriter()
+
+		go func() {
+			var (
+				open      []*openWindow[U]
+				watermark time.Time
+				timer     *time.Timer
+				timeout   <-chan time.Time
+			)
+
+			// passes on windows that end before watermark or every open window if all is true
+			emit := func(all bool) {
+				slices.SortFunc(open, func(a, b *openWindow[U]) int {
+					if c := a.End.Compare(b.End); c != 0 {
+						return c
+					}
+
+					return a.Start.Compare(b.Start)
+				})
+
+				closed := 0
+				for _, w := range open {
+					if !all && w.End.After(watermark) {
+						break
+					}
+
+					withAck(out, joinAcks(w.acks)).Write(w.Window)
+					closed++
+				}
+
+				open = slices.Delete(open, 0, closed)
+
+				if timer != nil {
+					timer.Stop()
+					timer, timeout = nil, nil
+				}
+
+				// processing time windows are closed by the clock
+				if o.eventTime == nil && len(open) > 0 {
+					timer = time.NewTimer(time.Until(open[0].End))
+					timeout = timer.C
+				}
+			}
+
+			defer out.Close()
+
+			for {
+				select {
+				case <-timeout:
+					watermark = time.Now()
+					emit(false)
+				case event, ok := <-r.Read():
+					if !ok {
+						emit(true)
+
+						return
+					}
+
+					a := event.ack
+					if event.Err != nil {
+						withAck(out, a).WriteError(event.Err)
+						a.done(nil)
+
+						continue
+					}
+
+					payload := event.Payload
+					r.Dispose(event)
+
+					t := time.Now()
+					if o.eventTime != nil {
+						t = o.eventTime(payload)
+					} else {
+						watermark = t
+					}
+
+					var late bool
+					if open, late = assign(open, payload, a, t, watermark); late {
+						withAck(out, a).WriteError(NewError(ErrLateEvent, payload))
+					}
+
+					a.done(nil)
+
+					if late {
+						continue
+					}
+
+					if o.eventTime != nil && t.Add(-o.lateness).After(watermark) {
+						watermark = t.Add(-o.lateness)
+					}
+
+					emit(false)
+				}
+			}
+		}()
+
+		return w, rw, pool
+	}
+}
@@ -0,0 +1,166 @@
+package pipelines_test
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/andriiyaremenko/pipelines"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type reading struct {
+	at    time.Time
+	value int
+}
+
+var _ = Describe("Window", func() {
+	ctx := context.TODO()
+	epoch := time.Unix(0, 0)
+
+	at := func(seconds ...int) []reading {
+		readings := make([]reading, len(seconds))
+		for i, s := range seconds {
+			readings[i] = reading{at: epoch.Add(time.Duration(s) * time.Second), value: s}
+		}
+
+		return readings
+	}
+
+	eventTime := pipelines.WithEventTime(func(r reading) time.Time { return r.at })
+
+	values := func(w pipelines.Window[reading]) []int {
+		result := make([]int, len(w.Events))
+		for i, e := range w.Events {
+			result[i] = e.value
+		}
+
+		return result
+	}
+
+	It("should group events into tumbling windows by event time", func() {
+		c := pipelines.TumblingWindow(pipelines.PassThrough[reading]().Pipeline(), time.Second*10, eventTime)
+
+		windows := [][]int{}
+		for w, err := range c.HandleSeq(ctx, slices.Values(at(1, 5, 12, 31, 35))) {
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(w.End.Sub(w.Start)).To(Equal(time.Second * 10))
+			windows = append(windows, values(w))
+		}
+
+		Expect(windows).To(Equal([][]int{{1, 5}, {12}, {31, 35}}))
+	})
+
+	It("should accept late events within allowed lateness", func() {
+		c := pipelines.TumblingWindow(
+			pipelines.PassThrough[reading]().Pipeline(),
+			time.Second*10,
+			eventTime,
+			pipelines.WithAllowedLateness[reading](time.Second*5),
+		)
+
+		windows := [][]int{}
+		late := []int{}
+		for w, err := range c.HandleSeq(ctx, slices.Values(at(1, 12, 8, 16, 3, 25))) {
+			if err != nil {
+				Expect(err).To(MatchError(pipelines.ErrLateEvent))
+
+				payload, _ := pipelines.PayloadOf[reading](err)
+				late = append(late, payload.value)
+
+				continue
+			}
+
+			windows = append(windows, values(w))
+		}
+
+		Expect(windows).To(Equal([][]int{{1, 8}, {12, 16}, {25}}))
+		Expect(late).To(Equal([]int{3}))
+	})
+
+	It("should group events into sliding windows", func() {
+		c := pipelines.SlidingWindow(pipelines.PassThrough[reading]().Pipeline(), time.Second*10, time.Second*5, eventTime)
+
+		windows := []pipelines.Window[reading]{}
+		for w, err := range c.HandleSeq(ctx, slices.Values(at(1, 7, 12))) {
+			Expect(err).ShouldNot(HaveOccurred())
+			windows = append(windows, w)
+		}
+
+		Expect(windows).To(HaveLen(4))
+		Expect(windows[0].Start).To(Equal(epoch.Add(-time.Second * 5)))
+		Expect(values(windows[0])).To(Equal([]int{1}))
+		Expect(values(windows[1])).To(Equal([]int{1, 7}))
+		Expect(values(windows[2])).To(Equal([]int{7, 12}))
+		Expect(values(windows[3])).To(Equal([]int{12}))
+	})
+
+	It("should align windows to Unix epoch", func() {
+		c := pipelines.TumblingWindow(pipelines.PassThrough[reading]().Pipeline(), time.Second*7, eventTime)
+
+		starts := []time.Time{}
+		for w, err := range c.HandleSeq(ctx, slices.Values(at(-1, 1, 8))) {
+			Expect(err).ShouldNot(HaveOccurred())
+			starts = append(starts, w.Start)
+		}
+
+		Expect(starts).To(Equal([]time.Time{epoch.Add(-time.Second * 7), epoch, epoch.Add(time.Second * 7)}))
+	})
+
+	It("should reject invalid window sizes", func() {
+		p := pipelines.PassThrough[reading]().Pipeline()
+
+		Expect(func() { pipelines.TumblingWindow(p, 0) }).To(Panic())
+		Expect(func() { pipelines.SlidingWindow(p, time.Second, 0) }).To(Panic())
+		Expect(func() { pipelines.SlidingWindow(p, time.Second, time.Second*2) }).To(Panic())
+		Expect(func() { pipelines.SessionWindow(p, -time.Second) }).To(Panic())
+		Expect(func() { pipelines.SlidingWindow(p, time.Second, time.Second) }).NotTo(Panic())
+	})
+
+	It("should group events into sessions", func() {
+		c := pipelines.SessionWindow(pipelines.PassThrough[reading]().Pipeline(), time.Second*5, eventTime)
+
+		windows := []pipelines.Window[reading]{}
+		for w, err := range c.HandleSeq(ctx, slices.Values(at(1, 3, 7, 20, 22, 40))) {
+			Expect(err).ShouldNot(HaveOccurred())
+			windows = append(windows, w)
+		}
+
+		Expect(windows).To(HaveLen(3))
+		Expect(values(windows[0])).To(Equal([]int{1, 3, 7}))
+		Expect(windows[0].Start).To(Equal(epoch.Add(time.Second)))
+		Expect(windows[0].End).To(Equal(epoch.Add(time.Second * 12)))
+		Expect(values(windows[1])).To(Equal([]int{20, 22}))
+		Expect(values(windows[2])).To(Equal([]int{40}))
+	})
+
+	It("should close processing time windows by the clock", func() {
+		payloads := make(chan int)
+		c := pipelines.TumblingWindow(pipelines.PassThrough[int]().Pipeline(), time.Millisecond*20)
+
+		go func() {
+			defer close(payloads)
+
+			payloads <- 1
+			payloads <- 2
+			time.Sleep(time.Millisecond * 100)
+			payloads <- 3
+		}()
+
+		windows := [][]int{}
+		for w, err := range c.HandleChan(ctx, payloads) {
+			Expect(err).ShouldNot(HaveOccurred())
+
+			if len(windows) == 0 {
+				// first window is closed while its stage still waits for the next event
+				Expect(time.Since(w.End)).To(BeNumerically("<", time.Millisecond*50))
+			}
+
+			windows = append(windows, w.Events)
+		}
+
+		Expect(slices.Concat(windows...)).To(Equal([]int{1, 2, 3}))
+		Expect(windows[len(windows)-1]).To(Equal([]int{3}))
+	})
+})
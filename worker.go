@@ -8,23 +8,99 @@ import (
 	"sync/atomic"
 )
 
-var ErrWorkerStopped = errors.New("command worker is stopped")
+var (
+	ErrWorkerStopped = errors.New("command worker is stopped")
+	ErrWorkerBusy    = errors.New("command worker is busy")
+)
 
 // Asynchronous Pipeline
 type Worker[T, U any] interface {
 	// Asynchronously handles Event and returns error if Worker is stopped.
+	// If admission queue is full behaves according to the OverflowPolicy of Worker.
 	Handle(T) error
 	// returns false if Worker was stopped.
 	IsRunning() bool
+	// Stops accepting new Events and waits for queued and in-flight executions to be handled by eventSink.
+	// Returns ctx error if ctx is done before Worker is drained.
+	// In-flight executions are not interrupted: cancel ctx passed to NewWorker to drop them.
+	Stop(ctx context.Context) error
+	// Returns channel that is closed once Worker is stopped and drained.
+	Done() <-chan struct{}
+}
+
+// OverflowPolicy defines Worker.Handle behaviour when admission queue is full.
+type OverflowPolicy int
+
+const (
+	// Worker.Handle blocks until there is space in admission queue.
+	OverflowBlock OverflowPolicy = iota
+	// Worker.Handle returns ErrWorkerBusy.
+	OverflowFail
+	// Worker.Handle drops the oldest queued Event to make space for the new one.
+	// With no admission queue it returns ErrWorkerBusy.
+	OverflowDropOldest
+)
+
+// WorkerOptions receives current Worker options and returns updated ones.
+type WorkerOptions func(workerOptions) workerOptions
+
+type workerOptions struct {
+	concurrency int
+	queue       int
+	overflow    OverflowPolicy
+}
+
+// Option that limits number of Pipeline executions Worker runs concurrently.
+func WithMaxConcurrency(n int) WorkerOptions {
+	return func(o workerOptions) workerOptions {
+		if n > 0 {
+			o.concurrency = n
+		}
+
+		return o
+	}
+}
+
+// Option that specifies how many Events Worker can hold waiting for execution
+// and what Worker.Handle does when they do not fit.
+func WithAdmissionQueue(size int, policy OverflowPolicy) WorkerOptions {
+	return func(o workerOptions) workerOptions {
+		if size > 0 {
+			o.queue = size
+		}
+
+		o.overflow = policy
+
+		return o
+	}
 }
 
 // Returns Worker based on `Pipeline[T, U]`.
 // eventSink is used to process the `Result[U]` of execution.
-func NewWorker[T, U any](ctx context.Context, eventSink func(iter.Seq2[U, error]), pipeline Pipeline[T, U]) Worker[T, U] {
+// By default Worker runs every Event as soon as it is received without limiting concurrency.
+func NewWorker[T, U any](
+	ctx context.Context,
+	eventSink func(iter.Seq2[U, error]),
+	pipeline Pipeline[T, U],
+	opts ...WorkerOptions,
+) Worker[T, U] {
+	var o workerOptions
+	for _, option := range opts {
+		o = option(o)
+	}
+
 	w := &worker[T, U]{
 		ctx:       ctx,
 		eventSink: eventSink,
 		pipeline:  pipeline,
+		overflow:  o.overflow,
+		queue:     make(chan T, o.queue),
+		stopping:  make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	if o.concurrency > 0 {
+		w.slots = make(chan struct{}, o.concurrency)
 	}
 
 	w.start()
@@ -35,9 +111,15 @@ func NewWorker[T, U any](ctx context.Context, eventSink func(iter.Seq2[U, error]
 type worker[T, U any] struct {
 	ctx       context.Context
 	pipeline  Pipeline[T, U]
-	eventPipe chan T
 	eventSink func(iter.Seq2[U, error])
+	overflow  OverflowPolicy
+	queue     chan T
+	slots     chan struct{}
+	admission sync.RWMutex
 	started   atomic.Bool
+	stopping  chan struct{}
+	stopOnce  sync.Once
+	done      chan struct{}
 }
 
 func (w *worker[T, U]) Handle(payload T) error {
@@ -45,30 +127,136 @@ func (w *worker[T, U]) Handle(payload T) error {
 		return ErrWorkerStopped
 	}
 
-	w.eventPipe <- payload
+	w.admission.RLock()
+	defer w.admission.RUnlock()
+
+	select {
+	case <-w.stopping:
+		return ErrWorkerStopped
+	case <-w.done:
+		return ErrWorkerStopped
+	default:
+	}
+
+	switch w.overflow {
+	case OverflowFail:
+		select {
+		case w.queue <- payload:
+			return nil
+		default:
+			return ErrWorkerBusy
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case w.queue <- payload:
+				return nil
+			default:
+			}
+
+			if cap(w.queue) == 0 {
+				return ErrWorkerBusy
+			}
 
-	return nil
+			select {
+			case <-w.queue:
+			default:
+			}
+		}
+	default:
+		select {
+		case <-w.stopping:
+			return ErrWorkerStopped
+		case <-w.done:
+			return ErrWorkerStopped
+		case w.queue <- payload:
+			return nil
+		}
+	}
 }
 
 func (w *worker[T, U]) IsRunning() bool {
 	return w.started.Load()
 }
 
+func (w *worker[T, U]) Stop(ctx context.Context) error {
+	w.stopOnce.Do(func() {
+		w.started.Store(false)
+		close(w.stopping)
+	})
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *worker[T, U]) Done() <-chan struct{} {
+	return w.done
+}
+
 func (w *worker[T, U]) start() {
 	if w.started.Load() {
 		return
 	}
 
-	w.eventPipe = make(chan T)
 	w.started.Store(true)
 
 	go func() {
 		var wg sync.WaitGroup
 		shutdown := func() {
+			w.started.Store(false)
 			wg.Wait()
 
-			w.started.Store(false)
-			close(w.eventPipe)
+			close(w.done)
+		}
+
+		// Starts Pipeline execution once concurrency limit allows it.
+		// Returns false if Worker ctx is done while waiting.
+		execute := func(event T) bool {
+			if w.slots != nil {
+				select {
+				case <-w.ctx.Done():
+					return false
+				case w.slots <- struct{}{}:
+				}
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				ctx, cancel := context.WithCancel(w.ctx)
+
+				w.eventSink(w.pipeline.Handle(ctx, event))
+
+				cancel()
+
+				if w.slots != nil {
+					<-w.slots
+				}
+			}()
+
+			return true
+		}
+
+		drain := func() {
+			// wait for Handle calls that are admitting Events right now
+			w.admission.Lock()
+			w.admission.Unlock()
+
+			for {
+				select {
+				case event := <-w.queue:
+					if !execute(event) {
+						return
+					}
+				default:
+					return
+				}
+			}
 		}
 
 		for {
@@ -76,6 +264,11 @@ func (w *worker[T, U]) start() {
 			case <-w.ctx.Done():
 				shutdown()
 
+				return
+			case <-w.stopping:
+				drain()
+				shutdown()
+
 				return
 			default:
 			}
@@ -85,17 +278,17 @@ func (w *worker[T, U]) start() {
 				shutdown()
 
 				return
-			case event := <-w.eventPipe:
-				wg.Add(1)
-				go func() {
-					defer wg.Done()
-
-					ctx, cancel := context.WithCancel(w.ctx)
+			case <-w.stopping:
+				drain()
+				shutdown()
 
-					w.eventSink(w.pipeline.Handle(ctx, event))
+				return
+			case event := <-w.queue:
+				if !execute(event) {
+					shutdown()
 
-					cancel()
-				}()
+					return
+				}
 			}
 		}
 	}()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"iter"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/andriiyaremenko/pipelines"
@@ -116,4 +117,165 @@ var _ = Describe("Worker", func() {
 		Expect(err).Should(HaveOccurred())
 		Expect(err).Should(MatchError(pipelines.ErrWorkerStopped))
 	})
+
+	It("should drain in-flight executions on Stop", func() {
+		var handled atomic.Int64
+
+		eventSink := func(result iter.Seq2[int, error]) {
+			for _, err := range result {
+				Expect(err).ShouldNot(HaveOccurred())
+				time.Sleep(time.Millisecond * 50)
+				handled.Add(1)
+			}
+		}
+
+		w := pipelines.NewWorker(context.TODO(), eventSink, pipelines.PassThrough[int]().Pipeline())
+
+		Expect(w.Handle(1)).Should(Succeed())
+		Expect(w.Handle(2)).Should(Succeed())
+		Expect(w.Handle(3)).Should(Succeed())
+
+		ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+		defer cancel()
+
+		Expect(w.Stop(ctx)).Should(Succeed())
+		Expect(handled.Load()).To(Equal(int64(3)))
+		Expect(w.IsRunning()).Should(BeFalse())
+		Expect(w.Done()).Should(BeClosed())
+		Expect(w.Handle(4)).Should(MatchError(pipelines.ErrWorkerStopped))
+	})
+
+	It("should return from Stop when ctx is done", func() {
+		gate := make(chan struct{})
+		eventSink := func(result iter.Seq2[int, error]) {
+			for range result {
+				<-gate
+			}
+		}
+
+		w := pipelines.NewWorker(context.TODO(), eventSink, pipelines.PassThrough[int]().Pipeline())
+
+		Expect(w.Handle(1)).Should(Succeed())
+
+		ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond*10)
+		defer cancel()
+
+		Expect(w.Stop(ctx)).Should(MatchError(context.DeadlineExceeded))
+		Expect(w.Done()).ShouldNot(BeClosed())
+
+		close(gate)
+
+		Eventually(w.Done()).Should(BeClosed())
+	})
+
+	It("should limit concurrent executions", func() {
+		var running, maxRunning, handled atomic.Int64
+
+		eventSink := func(result iter.Seq2[int, error]) {
+			n := running.Add(1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+
+			for range result {
+				time.Sleep(time.Millisecond * 10)
+			}
+
+			handled.Add(1)
+			running.Add(-1)
+		}
+
+		w := pipelines.NewWorker(
+			context.TODO(),
+			eventSink,
+			pipelines.PassThrough[int]().Pipeline(),
+			pipelines.WithMaxConcurrency(2),
+		)
+
+		for i := 0; i < 10; i++ {
+			Expect(w.Handle(i)).Should(Succeed())
+		}
+
+		Expect(w.Stop(context.TODO())).Should(Succeed())
+		Expect(handled.Load()).To(Equal(int64(10)))
+		Expect(maxRunning.Load()).To(BeNumerically("<=", 2))
+	})
+
+	It("should fail fast when admission queue is full", func() {
+		var handled atomic.Int64
+
+		gate := make(chan struct{})
+		eventSink := func(result iter.Seq2[int, error]) {
+			for range result {
+				<-gate
+			}
+
+			handled.Add(1)
+		}
+
+		w := pipelines.NewWorker(
+			context.TODO(),
+			eventSink,
+			pipelines.PassThrough[int]().Pipeline(),
+			pipelines.WithMaxConcurrency(1),
+			pipelines.WithAdmissionQueue(1, pipelines.OverflowFail),
+		)
+
+		accepted := 0
+		for i := 0; i < 10; i++ {
+			err := w.Handle(i)
+			if err != nil {
+				Expect(err).Should(MatchError(pipelines.ErrWorkerBusy))
+
+				continue
+			}
+
+			accepted++
+		}
+
+		// one event is executed, one is waiting for execution and one is in the queue
+		Expect(accepted).To(BeNumerically("<=", 3))
+
+		close(gate)
+
+		Expect(w.Stop(context.TODO())).Should(Succeed())
+		Expect(handled.Load()).To(Equal(int64(accepted)))
+	})
+
+	It("should drop oldest events when admission queue is full", func() {
+		var mu sync.Mutex
+		handled := []int{}
+
+		gate := make(chan struct{})
+		eventSink := func(result iter.Seq2[int, error]) {
+			for v := range result {
+				<-gate
+
+				mu.Lock()
+				handled = append(handled, v)
+				mu.Unlock()
+			}
+		}
+
+		w := pipelines.NewWorker(
+			context.TODO(),
+			eventSink,
+			pipelines.PassThrough[int]().Pipeline(),
+			pipelines.WithMaxConcurrency(1),
+			pipelines.WithAdmissionQueue(1, pipelines.OverflowDropOldest),
+		)
+
+		for i := 0; i < 10; i++ {
+			Expect(w.Handle(i)).Should(Succeed())
+		}
+
+		close(gate)
+
+		Expect(w.Stop(context.TODO())).Should(Succeed())
+		Expect(len(handled)).To(BeNumerically("<=", 3))
+		Expect(handled).To(ContainElement(9))
+	})
 })